@@ -0,0 +1,60 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// HistoryTimeLayout the layout of the -at flag, the time is in the local time zone.
+const HistoryTimeLayout = "2006-01-02 15:04"
+
+// printHistory prints why the symbol was quarantined or permitted at the given time
+// and how often the symbol was quarantined in the last days.
+func printHistory(a *autocoins.AutoCoins, symbol string, at string, days int) {
+	symbol = strings.ToUpper(symbol)
+	now := time.Now()
+
+	if at != "" {
+		t, err := time.ParseInLocation(HistoryTimeLayout, at, time.Local)
+		if err != nil {
+			logger.Fatalf("Invalid time '%s' (use %s)\n", at, HistoryTimeLayout)
+		}
+		record, err := a.SymbolAt(symbol, t)
+		if errors.Is(err, sql.ErrNoRows) {
+			fmt.Printf("%s was not processed before %s\n", symbol, at)
+		} else if err != nil {
+			logger.Fatalf("Unable to read history: %s\n", err.Error())
+		} else {
+			decision := "permitted"
+			if record.Quarantined {
+				decision = "quarantined"
+			} else if record.APIFailed {
+				decision = "not processed"
+			}
+			fmt.Printf("%s was %s at %s: %s\n", symbol, decision, record.Time.Format(HistoryTimeLayout), record.Reason())
+			for _, result := range record.Results {
+				fmt.Printf("  %-12s passed=%-5v %s\n", result.Rule, result.Passed, result.Reason)
+			}
+		}
+	}
+
+	quarantined, total, err := a.QuarantinedCount(symbol, now.AddDate(0, 0, -days), now)
+	if err != nil {
+		logger.Fatalf("Unable to read history: %s\n", err.Error())
+	}
+	fmt.Printf("%s was quarantined in %d of %d runs in the last %d days\n", symbol, quarantined, total, days)
+
+	failed, runs, err := a.FailedRuns(now.AddDate(0, 0, -days), now)
+	if err != nil {
+		logger.Fatalf("Unable to read history: %s\n", err.Error())
+	}
+	if failed > 0 {
+		fmt.Printf("%d of %d runs failed in the last %d days\n", failed, runs, days)
+	}
+}
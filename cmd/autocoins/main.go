@@ -3,33 +3,65 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 
 	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/autocoins/discordbot"
+	"github.com/LompeBoer/go-autocoins/internal/autocoins/server"
+	"github.com/LompeBoer/go-autocoins/internal/database/autocoinsdb"
+	"github.com/LompeBoer/go-autocoins/internal/database/klinedb"
 	"github.com/LompeBoer/go-autocoins/internal/discord"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange/bybit"
+	"github.com/LompeBoer/go-autocoins/internal/exchange/marketdata"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+	"github.com/LompeBoer/go-autocoins/internal/metrics"
+	"github.com/LompeBoer/go-autocoins/internal/rotate"
+	"github.com/LompeBoer/go-autocoins/internal/slack"
+	"github.com/LompeBoer/go-autocoins/internal/telegram"
 	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
 )
 
 const (
-	VersionNumber = "0.10.0"
+	VersionNumber      = "0.10.0"
+	KlineStoreFilename = "autocoins-klines.db"
+	StateFilename      = "autocoins.db"
 )
 
 func main() {
 	flags := initFlags()
 
-	log.Printf("Starting autocoins (v%s)\n\n", VersionNumber)
+	logger.Infof("Starting autocoins (v%s)\n\n", VersionNumber)
 
 	checkLatestVersion()
 
 	settings := autocoins.LoadConfig(flags.ConfigFilename)
+	initLogger(settings, flags.LogLevel, flags.StorageFilename)
 
 	autoCoins := initAutoCoins(settings, flags.StorageFilename)
-	if flags.SetPairs || flags.SetSafePairs {
+	if flags.History != "" {
+		printHistory(autoCoins, flags.History, flags.HistoryAt, flags.HistoryDays)
+	} else if flags.SetPairs || flags.SetSafePairs {
 		autoCoins.SetPairs(flags.SetSafePairs)
 	} else {
+		if settings.Metrics.Enabled {
+			m := startMetrics(autoCoins, settings.Metrics.Address)
+			defer m.Close()
+		}
+		if settings.Server.Enabled {
+			s := server.New(autoCoins, settings.Server.Address, settings.Server.Token)
+			s.Start()
+			defer s.Stop()
+		}
+		if settings.DiscordBot.Enabled {
+			b := startDiscordBot(autoCoins, settings)
+			defer b.Stop()
+		}
 		go autoCoins.Run()
 
 		stop := make(chan os.Signal, 1)
@@ -37,27 +69,23 @@ func main() {
 		<-stop
 		autoCoins.Stop()
 	}
-	log.Printf("Exiting autocoins")
+	logger.Infof("Exiting autocoins")
 }
 
-func initAutoCoins(settings *autocoins.Settings, storageFilename string) autocoins.AutoCoins {
+func initAutoCoins(settings *autocoins.Settings, storageFilename string) *autocoins.AutoCoins {
 	discordHook := discord.DiscordWebHook{
 		Enabled: true,
 		URL:     settings.Discord.WebHook,
 	}
-	autoCoins := autocoins.AutoCoins{
-		Settings: *settings,
-		ExchangeAPI: binance.NewAPI(binance.APIParams{
-			BaseURL:            "https://fapi.binance.com",
-			ProxyURL:           settings.Proxy.Address,
-			ProxyUser:          settings.Proxy.Username,
-			ProxyPassword:      settings.Proxy.Password,
-			DebugSaveResponses: false,
-			DebugReadResponses: false,
-		}),
+	exchangeAPI, derivativesAPI := initExchange(settings, storageFilename)
+	autoCoins := &autocoins.AutoCoins{
+		Settings:                   *settings,
+		ExchangeAPI:                exchangeAPI,
+		DerivativesAPI:             derivativesAPI,
 		BotAPI:                     wickhunter.NewAPI(settings.API),
 		MaxFailedSymbolsPercentage: 0.1,
 		StorageFilename:            storageFilename,
+		StateDB:                    initStateDB(storageFilename),
 		DisableWrite:               false,
 		OutputWriter: autocoins.OutputWriter{
 			Writers: []autocoins.Writer{
@@ -66,19 +94,194 @@ func initAutoCoins(settings *autocoins.Settings, storageFilename string) autocoi
 					WebHook:        discordHook,
 					Version:        VersionNumber,
 					MentionOnError: settings.Discord.MentionOnError,
+					Details:        settings.Discord.Details,
 				},
 			},
 		},
 	}
+	if settings.Telegram.Token != "" && settings.Telegram.ChatID != "" {
+		autoCoins.OutputWriter.Writers = append(autoCoins.OutputWriter.Writers, &autocoins.TelegramOutputWriter{
+			Bot:           telegram.NewBot(settings.Telegram.Token, settings.Telegram.ChatID),
+			Version:       VersionNumber,
+			Silent:        settings.Telegram.Silent,
+			NotifyOnError: settings.Telegram.NotifyOnError,
+		})
+	}
+	if settings.Outputs.Slack.WebHook != "" {
+		autoCoins.OutputWriter.Writers = append(autoCoins.OutputWriter.Writers, &autocoins.SlackOutputWriter{
+			WebHook:        slack.WebHook{URL: settings.Outputs.Slack.WebHook},
+			Version:        VersionNumber,
+			MentionOnError: settings.Outputs.Slack.MentionOnError,
+		})
+	}
+	for _, webhook := range settings.Outputs.Webhooks {
+		w, err := autocoins.NewWebhookOutputWriter(webhook, VersionNumber)
+		if err != nil {
+			logger.Fatalf("Invalid webhook '%s': %s\n", webhook.Name, err.Error())
+		}
+		autoCoins.OutputWriter.Writers = append(autoCoins.OutputWriter.Writers, w)
+	}
+	if settings.JSONLines.Enabled {
+		autoCoins.OutputWriter.Writers = append(autoCoins.OutputWriter.Writers, initJSONLines(settings, storageFilename))
+	}
 	return autoCoins
 }
 
+// initLogger sets the log level and starts writing to the log file, the level of the flag overrides the config file.
+// A relative log file is in the same directory as the storage file.
+func initLogger(settings *autocoins.Settings, level string, storageFilename string) {
+	if level == "" {
+		level = settings.Log.Level
+	}
+	l, err := logger.ParseLevel(level)
+	if err != nil {
+		logger.Fatalf("Invalid log level: %s\n", err.Error())
+	}
+	logger.SetLevel(l)
+
+	if settings.Log.File == "" {
+		return
+	}
+	filename := settings.Log.File
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(filepath.Dir(storageFilename), filename)
+	}
+	file := rotate.New(filename, int64(settings.Log.MaxSizeMB)*1024*1024, settings.Log.MaxBackups)
+	file.Daily = settings.Log.Daily
+	logger.SetOutput(io.MultiWriter(os.Stderr, file))
+	logger.Infof("Logging to %s\n", filename)
+}
+
+// startDiscordBot registers the slash commands and starts the interactions endpoint.
+func startDiscordBot(autoCoins *autocoins.AutoCoins, settings *autocoins.Settings) *discordbot.Bot {
+	s := settings.DiscordBot
+	b, err := discordbot.New(autoCoins, s.Address, s.PublicKey, s.Roles)
+	if err != nil {
+		logger.Fatalf("Invalid Discord bot settings: %s\n", err.Error())
+	}
+	if s.Token != "" && s.ApplicationID != "" {
+		if err := discord.RegisterCommands(discord.DefaultAPIURL, s.Token, s.ApplicationID, s.GuildID, discordbot.Commands); err != nil {
+			logger.Errorf("Unable to register the Discord commands: %s\n", err.Error())
+		}
+	}
+	if len(s.Roles) == 0 {
+		logger.Warnf("No Discord roles set, only /status and /swing can be used\n")
+	}
+	b.Start()
+	return b
+}
+
+// initJSONLines creates the JSON Lines writer, a relative file is in the same directory as the storage file.
+func initJSONLines(settings *autocoins.Settings, storageFilename string) *autocoins.JSONLinesOutputWriter {
+	filename := settings.JSONLines.File
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(filepath.Dir(storageFilename), filename)
+	}
+	return &autocoins.JSONLinesOutputWriter{
+		Writer:  rotate.New(filename, int64(settings.JSONLines.MaxSizeMB)*1024*1024, settings.JSONLines.MaxBackups),
+		Symbols: settings.JSONLines.Symbols,
+	}
+}
+
+// initExchange creates the exchange service configured in the settings.
+// When enabled the klines are persisted in a file next to the storage file.
+// The derivatives service is nil when the exchange does not provide the derivatives data.
+func initExchange(settings *autocoins.Settings, storageFilename string) (exchange.ExchangeService, exchange.DerivativesService) {
+	service, derivatives := initExchangeAPI(settings)
+	if !settings.MarketData.Store {
+		return service, derivatives
+	}
+
+	db := klinedb.New(filepath.Join(filepath.Dir(storageFilename), KlineStoreFilename))
+	if err := db.CreateKlineTable(); err != nil {
+		logger.Fatalf("Unable to create kline store: %s\n", err.Error())
+	}
+	return marketdata.NewStore(service, db), derivatives
+}
+
+// startMetrics serves the Prometheus metrics of the runs on /metrics.
+func startMetrics(autoCoins *autocoins.AutoCoins, address string) *http.Server {
+	registry := metrics.NewRegistry()
+	autoCoins.Metrics = autocoins.NewMetrics(registry)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+	s := &http.Server{Addr: address, Handler: mux}
+	logger.Infof("Metrics available on http://%s/metrics\n", address)
+	go func() {
+		if err := s.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("metrics: %s\n", err.Error())
+		}
+	}()
+	return s
+}
+
+// initStateDB opens the AutoCoins database in the same directory as the storage file.
+func initStateDB(storageFilename string) *autocoinsdb.Database {
+	db := autocoinsdb.New(filepath.Join(filepath.Dir(storageFilename), StateFilename))
+	if err := db.CreateQuarantineTable(); err != nil {
+		logger.Fatalf("Unable to create quarantine state table: %s\n", err.Error())
+	}
+	if err := db.CreateHistoryTables(); err != nil {
+		logger.Fatalf("Unable to create history tables: %s\n", err.Error())
+	}
+	return db
+}
+
+func initExchangeAPI(settings *autocoins.Settings) (exchange.ExchangeService, exchange.DerivativesService) {
+	switch settings.Exchange {
+	case "binance":
+		api, err := binance.NewAPI(binance.APIParams{
+			BaseURL:            "https://fapi.binance.com",
+			ProxyURL:           settings.Proxy.Address,
+			ProxyUser:          settings.Proxy.Username,
+			ProxyPassword:      settings.Proxy.Password,
+			DebugSaveResponses: false,
+			DebugReadResponses: false,
+		})
+		if err != nil {
+			logger.Fatalf("Unable to create Binance API: %s\n", err.Error())
+		}
+		api.FundingRates = settings.HasRule("funding")
+		api.OpenInterest = settings.HasRule("openInterest")
+		service := binance.NewService(api)
+		if settings.MarketData.Stream {
+			return marketdata.NewCache(service, binance.NewStream("wss://fstream.binance.com")), service
+		}
+		return service, service
+	case "bybit":
+		if settings.MarketData.Stream {
+			logger.Warnf("Streaming market data is not available for ByBit, using the REST API")
+		}
+		if settings.HasRule("funding") || settings.HasRule("openInterest") {
+			logger.Warnf("The funding and openInterest rules are not available for ByBit, they always pass")
+		}
+		api, err := bybit.NewAPI(bybit.APIParams{
+			BaseURL:       "https://api.bybit.com",
+			ProxyURL:      settings.Proxy.Address,
+			ProxyUser:     settings.Proxy.Username,
+			ProxyPassword: settings.Proxy.Password,
+		})
+		if err != nil {
+			logger.Fatalf("Unable to create ByBit API: %s\n", err.Error())
+		}
+		return bybit.NewService(api), nil
+	default:
+		logger.Fatalf("Unsupported exchange '%s' in config file.\n", settings.Exchange)
+	}
+	return nil, nil
+}
+
 type StartupFlags struct {
 	NoConfig        bool
 	ConfigFilename  string
 	StorageFilename string
 	SetPairs        bool
 	SetSafePairs    bool
+	History         string
+	HistoryAt       string
+	HistoryDays     int
+	LogLevel        string
 }
 
 func initFlags() StartupFlags {
@@ -88,6 +291,10 @@ func initFlags() StartupFlags {
 	storageFilename := flag.String("storage", "storage.db", "path to the storage file")
 	setPairs := flag.Bool("pairs", false, "set pairs to permitted from the Google Sheet Pairs List and exits the program")
 	setSafePairs := flag.Bool("safepairs", false, "set safe pairs to permitted from the Google Sheet Pairs List and exits the program")
+	history := flag.String("history", "", "prints the quarantine history of a symbol and exits the program")
+	historyAt := flag.String("at", "", "with -history prints why the symbol was quarantined at this time (\""+HistoryTimeLayout+"\")")
+	historyDays := flag.Int("days", 30, "with -history the number of days to count the quarantined runs")
+	logLevel := flag.String("loglevel", "", "debug, info, warn or error, overrides the level in the config file")
 	flag.Parse()
 
 	if *version {
@@ -95,13 +302,18 @@ func initFlags() StartupFlags {
 		os.Exit(0)
 	}
 
+	if *logLevel != "" {
+		if _, err := logger.ParseLevel(*logLevel); err != nil {
+			logger.Fatalf("Invalid -loglevel: %s\n", err.Error())
+		}
+	}
 	if _, err := os.Stat(*storageFilename); os.IsNotExist(err) {
-		log.Fatalf("Storage file '%s' does not exist.\n", *storageFilename)
+		logger.Fatalf("Storage file '%s' does not exist.\n", *storageFilename)
 	}
 	if *noConfig {
 		*configFilename = ""
 	} else if _, err := os.Stat(*configFilename); os.IsNotExist(err) {
-		log.Fatalf("Config file '%s' does not exist.\n", *configFilename)
+		logger.Fatalf("Config file '%s' does not exist.\n", *configFilename)
 	}
 
 	return StartupFlags{
@@ -110,5 +322,9 @@ func initFlags() StartupFlags {
 		StorageFilename: *storageFilename,
 		SetPairs:        *setPairs,
 		SetSafePairs:    *setSafePairs,
+		History:         *history,
+		HistoryAt:       *historyAt,
+		HistoryDays:     *historyDays,
+		LogLevel:        *logLevel,
 	}
 }
@@ -2,9 +2,9 @@ package main
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/LompeBoer/go-autocoins/internal/github"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
 	"golang.org/x/mod/semver"
 )
 
@@ -17,7 +17,7 @@ func checkLatestVersion() {
 	githubAPI := github.NewAPI(Owner, Repo)
 	release, err := githubAPI.LatestRelease()
 	if err != nil {
-		log.Printf("Unable to get version information: %s\n", err.Error())
+		logger.Warnf("Unable to get version information: %s\n", err.Error())
 	}
 
 	if semver.Compare("v"+VersionNumber, "v"+release.TagName) < 0 {
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/backtest"
+)
+
+// TimeLayout the layout of the -from and -to flags, the time is in UTC.
+const TimeLayout = "2006-01-02 15:04"
+
+func main() {
+	configFilename := flag.String("config", "autoCoins.json", "path to the config file, the default settings are used when it does not exist")
+	dataDir := flag.String("data", "klines", "directory with the klines (SYMBOL-1m.json, SYMBOL-month.json and optional exchangeInfo.json)")
+	debugResponses := flag.Bool("debug", false, "the data directory contains responses saved with DebugSaveResponses")
+	from := flag.String("from", "", "first step (\""+TimeLayout+"\" UTC), default 24 hours after the first kline")
+	to := flag.String("to", "", "last step (\""+TimeLayout+"\" UTC), default the last kline")
+	step := flag.Int("step", 0, "minutes between the steps, default the refresh interval of the config")
+	move := flag.Float64("move", 10, "a price move of at least this percent is a large move")
+	lookahead := flag.Int("lookahead", 4, "hours after a step checked for a large move")
+	timeline := flag.Bool("timeline", false, "prints every change between permitted and quarantined")
+	var parameters stringList
+	var objectives stringList
+	flag.Var(&parameters, "param", "sweep a setting, name=1,2,3 or name=min:max:step (repeatable)")
+	flag.Var(&objectives, "objective", "score of the sweep, name:moveWeight:hourWeight:flipWeight (repeatable)")
+	random := flag.Int("random", 0, "number of random combinations of the sweep, 0 tries all combinations")
+	seed := flag.Int64("seed", 1, "seed of the random combinations")
+	out := flag.String("out", "", "write the sweep results to this file (.json or .csv)")
+	flag.Parse()
+
+	if _, err := os.Stat(*configFilename); os.IsNotExist(err) {
+		*configFilename = ""
+	}
+	settings := autocoins.LoadConfig(*configFilename)
+
+	var e *backtest.Exchange
+	var err error
+	if *debugResponses {
+		e, err = backtest.LoadDebugResponses(*dataDir, "https://fapi.binance.com")
+	} else {
+		e, err = backtest.LoadDirectory(*dataDir)
+	}
+	if err != nil {
+		log.Fatalf("Unable to load klines: %s\n", err.Error())
+	}
+
+	options := backtest.Options{
+		From:             parseTime(*from),
+		To:               parseTime(*to),
+		Step:             time.Duration(settings.Refresh) * time.Minute,
+		LargeMovePercent: *move,
+		LookaheadHours:   *lookahead,
+	}
+	if *step > 0 {
+		options.Step = time.Duration(*step) * time.Minute
+	}
+
+	if len(parameters) > 0 {
+		sweep(*settings, e, backtest.SweepOptions{
+			Options:    options,
+			Parameters: parseParameters(parameters),
+			Objectives: parseObjectives(objectives),
+			Random:     *random,
+			Seed:       *seed,
+		}, *out)
+		return
+	}
+
+	result, err := backtest.Run(&autocoins.AutoCoins{Settings: *settings}, e, options)
+	if err != nil {
+		log.Fatalf("Backtest failed: %s\n", err.Error())
+	}
+
+	if *timeline {
+		printTimeline(result)
+	}
+	printResult(result)
+}
+
+func parseTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(TimeLayout, value)
+	if err != nil {
+		log.Fatalf("Invalid time '%s' (use %s)\n", value, TimeLayout)
+	}
+	return t
+}
+
+func printTimeline(result *backtest.Result) {
+	previous := map[string]bool{}
+	for _, step := range result.Steps {
+		changes := []string{}
+		for _, s := range step.Quarantined {
+			if !previous[s] {
+				changes = append(changes, fmt.Sprintf("-%s (%s)", s, step.Reasons[s]))
+			}
+		}
+		for _, s := range step.Permitted {
+			if quarantined, ok := previous[s]; ok && quarantined {
+				changes = append(changes, "+"+s)
+			}
+		}
+		for _, s := range step.Quarantined {
+			previous[s] = true
+		}
+		for _, s := range step.Permitted {
+			previous[s] = false
+		}
+		if len(changes) > 0 {
+			fmt.Printf("%s %s\n", step.Time.UTC().Format(TimeLayout), strings.Join(changes, " "))
+		}
+	}
+	fmt.Println()
+}
+
+func printResult(result *backtest.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Symbol\tSteps\tQuarantined\tFlips\tLarge moves\tQuarantined ahead\t")
+	for _, s := range result.Symbols {
+		fmt.Fprintf(w, "%s\t%d\t%.1f%%\t%d\t%d\t%d\t\n", s.Symbol, s.Steps, s.QuarantinedPercent, s.Flips, s.LargeMoves, s.LargeMovesQuarantined)
+	}
+	w.Flush()
+
+	summary := result.Summary
+	fmt.Printf("\nSteps: %d Quarantined: %.1f%% Flips: %d Large moves quarantined ahead: %d/%d (%.1f%%)\n",
+		summary.Steps, summary.QuarantinedPercent, summary.Flips, summary.LargeMovesQuarantined, summary.LargeMoves, summary.LargeMovesQuarantinedPercent)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/backtest"
+)
+
+// SweepPrintLimit the number of best results printed.
+const SweepPrintLimit = 10
+
+// stringList a flag that can be used multiple times.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func parseParameters(values []string) []backtest.Parameter {
+	parameters := []backtest.Parameter{}
+	for _, v := range values {
+		p, err := backtest.ParseParameter(v)
+		if err != nil {
+			log.Fatalf("Invalid -param: %s\n", err.Error())
+		}
+		parameters = append(parameters, p)
+	}
+	return parameters
+}
+
+func parseObjectives(values []string) []backtest.Objective {
+	if len(values) == 0 {
+		return backtest.DefaultObjectives
+	}
+	objectives := []backtest.Objective{}
+	for _, v := range values {
+		o, err := backtest.ParseObjective(v)
+		if err != nil {
+			log.Fatalf("Invalid -objective: %s\n", err.Error())
+		}
+		objectives = append(objectives, o)
+	}
+	return objectives
+}
+
+func sweep(settings autocoins.Settings, e *backtest.Exchange, options backtest.SweepOptions, out string) {
+	results, err := backtest.Sweep(settings, e, options)
+	if err != nil {
+		log.Fatalf("Sweep failed: %s\n", err.Error())
+	}
+
+	if out != "" {
+		if err := writeSweep(out, results, options); err != nil {
+			log.Fatalf("Unable to write %s: %s\n", out, err.Error())
+		}
+		log.Printf("Wrote %d results to %s\n", len(results), out)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := []string{}
+	for _, p := range options.Parameters {
+		header = append(header, p.Name)
+	}
+	header = append(header, "Quarantined", "Hours", "Flips", "Quarantined ahead")
+	for _, o := range options.Objectives {
+		header = append(header, o.Name)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t")+"\t")
+	for i, r := range results {
+		if i == SweepPrintLimit {
+			break
+		}
+		row := []string{}
+		for _, p := range options.Parameters {
+			row = append(row, fmt.Sprintf("%g", r.Settings[p.Name]))
+		}
+		s := r.Summary
+		row = append(row,
+			fmt.Sprintf("%.1f%%", s.QuarantinedPercent),
+			fmt.Sprintf("%.0f", s.QuarantinedHours),
+			fmt.Sprintf("%d", s.Flips),
+			fmt.Sprintf("%d/%d", s.LargeMovesQuarantined, s.LargeMoves),
+		)
+		for _, o := range options.Objectives {
+			row = append(row, fmt.Sprintf("%.1f", r.Scores[o.Name]))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t")+"\t")
+	}
+	w.Flush()
+}
+
+func writeSweep(filename string, results []backtest.SweepResult, options backtest.SweepOptions) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		return backtest.WriteSweepJSON(f, results, options.Objectives)
+	}
+	return backtest.WriteSweepCSV(f, results, options.Parameters, options.Objectives)
+}
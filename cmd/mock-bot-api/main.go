@@ -2,61 +2,62 @@ package main
 
 import (
 	"database/sql"
-	"encoding/json"
+	"flag"
 	"log"
 	"net/http"
 
 	"github.com/LompeBoer/go-autocoins/internal/database/whdbv1"
 	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter/wickhuntertest"
 )
 
-type Controller struct {
-	db *whdbv1.Database
-}
-
+// The mock serves the local API of WickHunter with the symbols and positions of a WickHunter database.
+// Changes are kept in memory, the database is not changed.
 func main() {
-	c := Controller{
-		db: whdbv1.New("storage.db"),
-	}
+	filename := flag.String("db", "storage.db", "The WickHunter database")
+	address := flag.String("address", ":5001", "The address to listen on")
+	flag.Parse()
 
-	http.HandleFunc("/bot/positions", c.handlePositions)
+	db := whdbv1.New(*filename)
+	defer db.Close()
+	server := wickhuntertest.New()
+	load(server, db)
 
-	log.Fatal(http.ListenAndServe(":5001", nil))
+	log.Printf("Serving the WickHunter API on %s\n", *address)
+	log.Fatal(http.ListenAndServe(*address, server.Handler()))
 }
 
-func (c *Controller) handlePositions(w http.ResponseWriter, r *http.Request) {
-	w.Header().Add("content-type", "application/json")
-	w.Header().Add("expires", "0")
-	w.Header().Add("pragma", "no-cache")
-
-	instruments, err := c.db.SelectInstruments()
+func load(server *wickhuntertest.Server, db *whdbv1.Database) {
+	instruments, err := db.SelectInstruments()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	retval := []wickhunter.Position{}
 	for _, ins := range instruments {
-		state, err := c.db.SelectPositionState(ins.Symbol.String)
+		symbol := ins.Symbol.String
+		server.AddSymbol(wickhunter.SymbolSettings{
+			Symbol:          symbol,
+			Permitted:       ins.IsPermitted,
+			DefaultSettings: ins.IsDefaultSetting,
+		})
+
+		state, err := db.SelectPositionState(symbol)
 		if err != nil && err != sql.ErrNoRows {
 			log.Fatal(err)
 		}
-
-		status := state.Status
-		if status == "" {
-			status = "Neutral"
+		if state.Status == "" {
+			continue
 		}
-
-		retval = append(retval, wickhunter.Position{
-			Symbol:    ins.Symbol.String,
-			Permitted: ins.IsPermitted,
-			State:     status,
+		server.SetPosition(wickhunter.Position{
+			Symbol:          symbol,
+			State:           state.Status,
+			Side:            state.Side,
+			Size:            state.Quantity,
+			EntryPrice:      state.AveragePrice,
+			DCACount:        int(state.BuyCount),
+			TakeProfitPrice: state.TakeProfitPrice,
+			StopLossPrice:   state.StopLossPrice,
 		})
 	}
-
-	b, err := json.Marshal(retval)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	w.Write(b)
+	log.Printf("Loaded %d symbols\n", len(instruments))
 }
@@ -5,33 +5,47 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/database/autocoinsdb"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 	"github.com/LompeBoer/go-autocoins/internal/pairslist"
 	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
 )
 
 type AutoCoins struct {
 	Settings                   Settings
-	ExchangeAPI                *binance.API
+	ExchangeAPI                exchange.ExchangeService
+	DerivativesAPI             exchange.DerivativesService // DerivativesAPI (optional) for the funding rate and open interest rules.
 	BotAPI                     *wickhunter.API
 	ctx                        context.Context
 	cancel                     context.CancelFunc
 	wg                         sync.WaitGroup
-	IsRunning                  bool
 	MaxFailedSymbolsPercentage float64
 	StorageFilename            string
 	DisableWrite               bool
 	OutputWriter               OutputWriter
+	Clock                      func() time.Time      // Clock (optional) returns the current time, replaced when backtesting.
+	Metrics                    *Metrics              // Metrics (optional) updated after every run.
+	StateDB                    *autocoinsdb.Database // StateDB (optional) stores the quarantine state and the run history.
+	quarantineStates           map[string]autocoinsdb.QuarantineState
+	mutex                      sync.Mutex // mutex guards the state below and the Settings, they are used by the control API.
+	lastRun                    *RunStatus
+	trigger                    chan struct{}
+	paused                     bool
+	running                    bool
+	overrides                  []Override
+	schedule                   string
 }
 
 // GetInfo retrieves all symbol data and calculates market swing.
 // It returns a list of permitted coins to trade.
 func (a *AutoCoins) GetInfo(pairsList []pairslist.Pair) ([]SymbolDataObject, SymbolLists, error) {
-	exchangeInfo, err := a.ExchangeAPI.GetExchangeInfo()
+	exchangeSymbols, err := a.ExchangeAPI.GetSymbols()
 	if err != nil {
 		return nil, SymbolLists{}, err
 	}
+	a.pruneQuarantineStates(exchangeSymbols)
 
 	usedSymbols, err := a.BotAPI.GetPositions()
 	if err != nil {
@@ -39,35 +53,26 @@ func (a *AutoCoins) GetInfo(pairsList []pairslist.Pair) ([]SymbolDataObject, Sym
 	}
 
 	// Remove symbols from the list based on the enabled filters.
-	symbols, err := a.filterSymbols(usedSymbols, exchangeInfo.Symbols, pairsList)
+	symbols, err := a.filterSymbols(usedSymbols, exchangeSymbols, pairsList)
 	if err != nil {
 		return nil, SymbolLists{}, fmt.Errorf("unable to filter symbols: %s", err.Error())
 	}
-	sort.Sort(binance.BySymbolName(symbols))
+	sort.Sort(exchange.BySymbolName(symbols))
 
 	// Will pause execution when rate limit will be exceeded.
 	a.ExchangeAPI.RateLimitChecks(len(symbols))
 
-	prices24Hours, err := a.ExchangeAPI.GetTicker()
+	objects, err := a.CalculateSymbols(symbols)
 	if err != nil {
 		return nil, SymbolLists{}, err
 	}
 
-	c := make(chan SymbolDataObject)
-	count := a.RetrieveAllSymbolData(symbols, prices24Hours, c)
-
-	objects := []SymbolDataObject{}
-	for i := 0; i < count; i++ {
-		object := <-c
-		objects = append(objects, object)
-	}
-
 	positions, err := a.BotAPI.GetPositions()
 	if err != nil {
 		return nil, SymbolLists{}, fmt.Errorf("botapi:getpositions: %s", err.Error())
 	}
 
-	lists, err := a.makeLists(objects, positions)
+	lists, err := a.MakeLists(objects, positions)
 	if err != nil {
 		return nil, SymbolLists{}, fmt.Errorf("unable to make list: %s", err.Error())
 	}
@@ -75,12 +80,45 @@ func (a *AutoCoins) GetInfo(pairsList []pairslist.Pair) ([]SymbolDataObject, Sym
 	// If not enough symbol data is retrieved from the API fail this run.
 	percentageFailed := float64(len(lists.FailedToProcess)) / float64(len(symbols))
 	if percentageFailed > a.MaxFailedSymbolsPercentage {
-		return nil, SymbolLists{}, fmt.Errorf("unable to retrieve enough data from Binance API (%.0f%% failed)", percentageFailed*100)
+		return nil, SymbolLists{}, fmt.Errorf("unable to retrieve enough data from %s API (%.0f%% failed)", a.Settings.Exchange, percentageFailed*100)
 	}
 
 	return objects, lists, nil
 }
 
+// CalculateSymbols retrieves the data of all the symbols and checks the quarantine rules.
+func (a *AutoCoins) CalculateSymbols(symbols []exchange.Symbol) ([]SymbolDataObject, error) {
+	shared, err := a.retrieveSharedData()
+	if err != nil {
+		return nil, err
+	}
+
+	c := make(chan SymbolDataObject)
+	count := a.RetrieveAllSymbolData(symbols, shared, c)
+
+	objects := []SymbolDataObject{}
+	for i := 0; i < count; i++ {
+		object := <-c
+		objects = append(objects, object)
+	}
+
+	a.applyThresholdOverrides(objects)
+	if a.Settings.Regime.Enabled {
+		a.applyRegime(objects)
+	}
+	a.applyHysteresis(objects, a.now())
+
+	return objects, nil
+}
+
+// now returns the current time of the Clock.
+func (a *AutoCoins) now() time.Time {
+	if a.Clock != nil {
+		return a.Clock()
+	}
+	return time.Now()
+}
+
 // SymbolLists contains all the calculated lists.
 type SymbolLists struct {
 	Quarantined          []string // Quarantined symbols to quarantine.
@@ -95,8 +133,8 @@ type SymbolLists struct {
 	NotTrading           []string // NotTrading coins that are excluded from trading.
 }
 
-// makeLists makes the SymbolLists object, this groups all the symbols in a certain list.
-func (a *AutoCoins) makeLists(objects []SymbolDataObject, positions []wickhunter.Position) (SymbolLists, error) {
+// MakeLists makes the SymbolLists object, this groups all the symbols in a certain list.
+func (a *AutoCoins) MakeLists(objects []SymbolDataObject, positions []wickhunter.Position) (SymbolLists, error) {
 	openPositions := []string{}
 	permittedCurrently := []string{}
 	quarantinedCurrently := []string{}
@@ -199,10 +237,44 @@ func (a *AutoCoins) makeLists(objects []SymbolDataObject, positions []wickhunter
 	}, nil
 }
 
-func (a *AutoCoins) RetrieveAllSymbolData(symbols []binance.Symbol, prices24Hours []binance.Ticker, c chan SymbolDataObject) int {
+// SharedData the exchange data that is retrieved once per run for all symbols.
+type SharedData struct {
+	Prices24Hours     []exchange.Ticker
+	FundingRates      []exchange.FundingRate
+	CorrelationKlines []exchange.Kline // CorrelationKlines the 1 minute klines of the regime correlation symbol.
+}
+
+func (a *AutoCoins) retrieveSharedData() (*SharedData, error) {
+	prices24Hours, err := a.ExchangeAPI.GetTickers()
+	if err != nil {
+		return nil, err
+	}
+	shared := &SharedData{Prices24Hours: prices24Hours}
+
+	if a.DerivativesAPI != nil && a.Settings.HasRule("funding") {
+		rates, err := a.DerivativesAPI.GetFundingRates()
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve funding rates: %s", err.Error())
+		}
+		shared.FundingRates = rates
+	}
+
+	if a.Settings.Regime.Enabled && a.Settings.Regime.CorrelationSymbol != "" {
+		symbol := exchange.Symbol{Name: a.Settings.Regime.CorrelationSymbol}
+		klines, err := a.ExchangeAPI.GetKline(symbol, exchange.OneMinute, a.klineHours()*60)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve %s klines: %s", symbol.Name, err.Error())
+		}
+		shared.CorrelationKlines = klines
+	}
+
+	return shared, nil
+}
+
+func (a *AutoCoins) RetrieveAllSymbolData(symbols []exchange.Symbol, shared *SharedData, c chan SymbolDataObject) int {
 	count := 0
 	for _, symbol := range symbols {
-		go a.RetrieveSymbolData(symbol, &prices24Hours, c)
+		go a.RetrieveSymbolData(symbol, shared, c)
 		count++
 	}
 	return count
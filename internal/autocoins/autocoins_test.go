@@ -4,7 +4,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 	"github.com/LompeBoer/go-autocoins/internal/pairslist"
 	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
 )
@@ -12,16 +12,16 @@ import (
 func TestMakeLists(t *testing.T) {
 	objects := []SymbolDataObject{
 		{
-			Symbol:    binance.Symbol{Name: "TEST"},
+			Symbol:    exchange.Symbol{Name: "TEST"},
 			Open:      false,
 			Time:      time.Now(),
 			APIFailed: false,
-			Result: SymbolDataResult{
-				Percent1Hour:  true,
-				Percent4Hour:  true,
-				Percent24Hour: true,
-				AllTimeHigh:   true,
-				Age:           true,
+			Results: []RuleResult{
+				{Rule: "1hr", Passed: true},
+				{Rule: "4hr", Passed: true},
+				{Rule: "24hr", Passed: true},
+				{Rule: "ath", Passed: true},
+				{Rule: "age", Passed: true},
 			},
 			Values: SymbolDataValues{
 				Percent1Hour:  []float64{0, 1, 2, 4},
@@ -40,7 +40,7 @@ func TestMakeLists(t *testing.T) {
 		},
 	}
 	a := AutoCoins{}
-	lists, err := a.makeLists(objects, positions)
+	lists, err := a.MakeLists(objects, positions)
 	if err != nil {
 		t.Errorf("error returned: %s", err.Error())
 	}
@@ -75,8 +75,8 @@ func TestFilterSymbols(t *testing.T) {
 		},
 	}
 
-	binanceSymbols := []binance.Symbol{{Name: "TEST"}}
-	symbols, err := a.filterSymbols(positions, binanceSymbols, []pairslist.Pair{})
+	exchangeSymbols := []exchange.Symbol{{Name: "TEST"}}
+	symbols, err := a.filterSymbols(positions, exchangeSymbols, []pairslist.Pair{})
 	if err != nil {
 		t.Errorf("filterSymbols returned error: %s", err.Error())
 	}
@@ -87,3 +87,42 @@ func TestFilterSymbols(t *testing.T) {
 		t.Errorf("invalid filter: got %d expect %d", got, expect)
 	}
 }
+
+func TestApplyOverrides(t *testing.T) {
+	a := AutoCoins{
+		Settings: Settings{
+			Filters: SettingsFilters{
+				ExcludeList: []string{"BBB"},
+			},
+		},
+	}
+	if _, err := a.AddOverride("AAA", OverrideExclude, 0); err != nil {
+		t.Fatalf("AddOverride returned error: %s", err.Error())
+	}
+	if _, err := a.AddOverride("CCC", OverrideBlackList, time.Minute); err != nil {
+		t.Fatalf("AddOverride returned error: %s", err.Error())
+	}
+	a.overrides[1].Until = time.Now().Add(-time.Second)
+
+	a.applyOverrides()
+	a.applyOverrides()
+
+	if !equalStrings(a.Settings.Filters.ExcludeList, []string{"AAA", "BBB"}) {
+		t.Errorf("invalid exclude list: expected %v got %v", []string{"AAA", "BBB"}, a.Settings.Filters.ExcludeList)
+	}
+	if len(a.Settings.Filters.BlackList) != 0 {
+		t.Errorf("expired override applied: %v", a.Settings.Filters.BlackList)
+	}
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
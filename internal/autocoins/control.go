@@ -0,0 +1,194 @@
+package autocoins
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+// RunStatus the result of the last run.
+type RunStatus struct {
+	Time         time.Time          `json:"time"`
+	Duration     float64            `json:"durationSeconds"`
+	Error        string             `json:"error,omitempty"`
+	Schedule     string             `json:"schedule,omitempty"` // Schedule the name of the schedule used by the run.
+	Lists        SymbolLists        `json:"lists"`
+	MarketSwings []MarketSwing      `json:"marketSwings"`
+	Symbols      []SymbolDataObject `json:"symbols"`
+	Weight       exchange.Weight    `json:"weight"`
+}
+
+// Override temporarily adds a symbol to the exclude list or the blacklist without changing the config file.
+type Override struct {
+	Symbol string    `json:"symbol"`
+	List   string    `json:"list"`            // List "exclude" or "blacklist".
+	Until  time.Time `json:"until,omitempty"` // Until the override expires, zero keeps it until the program exits.
+}
+
+const (
+	OverrideExclude   = "exclude"
+	OverrideBlackList = "blacklist"
+)
+
+// LastRun returns the status of the last run, false when no run finished yet.
+func (a *AutoCoins) LastRun() (RunStatus, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.lastRun == nil {
+		return RunStatus{}, false
+	}
+	return *a.lastRun, true
+}
+
+func (a *AutoCoins) setLastRun(objects []SymbolDataObject, lists SymbolLists, startTime time.Time, err error) {
+	status := &RunStatus{
+		Time:     startTime,
+		Duration: time.Since(startTime).Seconds(),
+		Lists:    lists,
+		Symbols:  objects,
+		Weight:   a.ExchangeAPI.Weight(),
+	}
+	if len(objects) > 0 {
+		status.MarketSwings = CalculateMarketSwing(objects)
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	status.Schedule = a.schedule
+	a.lastRun = status
+}
+
+// CurrentSettings returns a copy of the settings used by the next run.
+func (a *AutoCoins) CurrentSettings() Settings {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.Settings
+}
+
+// TriggerRun starts a run immediately, also when the loop is paused.
+func (a *AutoCoins) TriggerRun() {
+	select {
+	case a.triggerChannel() <- struct{}{}:
+	default:
+		// A run is already triggered.
+	}
+}
+
+func (a *AutoCoins) triggerChannel() chan struct{} {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.trigger == nil {
+		a.trigger = make(chan struct{}, 1)
+	}
+	return a.trigger
+}
+
+// Pause skips the scheduled runs until Resume is called.
+func (a *AutoCoins) Pause() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.paused = true
+}
+
+func (a *AutoCoins) Resume() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.paused = false
+}
+
+// IsRunning returns true when the run loop is started and not stopped.
+func (a *AutoCoins) IsRunning() bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.running
+}
+
+func (a *AutoCoins) IsPaused() bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.paused
+}
+
+// AddOverride adds the symbol to the list for the duration, a duration of 0 keeps it until the program exits.
+// The override is used from the next run.
+func (a *AutoCoins) AddOverride(symbol string, list string, duration time.Duration) (Override, error) {
+	if list != OverrideExclude && list != OverrideBlackList {
+		return Override{}, fmt.Errorf("unknown list '%s' (use %s or %s)", list, OverrideExclude, OverrideBlackList)
+	}
+	if symbol == "" {
+		return Override{}, fmt.Errorf("no symbol")
+	}
+	override := Override{Symbol: symbol, List: list}
+	if duration > 0 {
+		override.Until = time.Now().Add(duration)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.removeOverride(symbol, list)
+	a.overrides = append(a.overrides, override)
+	return override, nil
+}
+
+// RemoveOverride removes the override, returns false when there is no such override.
+func (a *AutoCoins) RemoveOverride(symbol string, list string) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.removeOverride(symbol, list)
+}
+
+func (a *AutoCoins) removeOverride(symbol string, list string) bool {
+	for i, o := range a.overrides {
+		if o.Symbol == symbol && o.List == list {
+			a.overrides = append(a.overrides[:i], a.overrides[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Overrides returns the overrides that did not expire.
+func (a *AutoCoins) Overrides() []Override {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.expireOverrides(time.Now())
+	return append([]Override{}, a.overrides...)
+}
+
+func (a *AutoCoins) expireOverrides(now time.Time) {
+	active := a.overrides[:0]
+	for _, o := range a.overrides {
+		if o.Until.IsZero() || o.Until.After(now) {
+			active = append(active, o)
+		}
+	}
+	a.overrides = active
+}
+
+// applyOverrides adds the symbols of the overrides to the exclude list and the blacklist of the settings.
+func (a *AutoCoins) applyOverrides() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.expireOverrides(time.Now())
+
+	filters := &a.Settings.Filters
+	for _, o := range a.overrides {
+		switch o.List {
+		case OverrideExclude:
+			if !ContainsString(filters.ExcludeList, o.Symbol) {
+				filters.ExcludeList = append(append([]string{}, filters.ExcludeList...), o.Symbol)
+			}
+		case OverrideBlackList:
+			if !ContainsString(filters.BlackList, o.Symbol) {
+				filters.BlackList = append(append([]string{}, filters.BlackList...), o.Symbol)
+			}
+		}
+	}
+	sort.Strings(filters.ExcludeList)
+	sort.Strings(filters.BlackList)
+}
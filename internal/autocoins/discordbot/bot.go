@@ -0,0 +1,351 @@
+// Package discordbot controls a running AutoCoins with Discord slash commands.
+//
+// Discord sends the commands to the interactions endpoint of the application, this has to be a public
+// HTTPS url, for example a reverse proxy in front of the address of the bot.
+package discordbot
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/discord"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// Path of the interactions endpoint.
+const Path = "/interactions"
+
+// botAPITimeout the maximum duration of a WickHunter update, Discord needs the response within 3 seconds.
+const botAPITimeout = 2 * time.Second
+
+var symbolPattern = regexp.MustCompile(`^[A-Z0-9]{2,20}$`)
+
+// Commands the slash commands of the bot.
+var Commands = []discord.ApplicationCommand{
+	{Name: "status", Description: "Show the status and the lists of the last run"},
+	{Name: "swing", Description: "Show the market swing of the last run"},
+	{Name: "run", Description: "Start a run now"},
+	{Name: "quarantine", Description: "Quarantine a coin until /permit", Options: []discord.CommandOption{
+		{Type: discord.OptionString, Name: "symbol", Description: "The symbol, for example XYZUSDT", Required: true},
+		{Type: discord.OptionString, Name: "duration", Description: "For example 2h or 1d, until the program exits when not set"},
+	}},
+	{Name: "permit", Description: "Permit a coin and exclude it from being quarantined", Options: []discord.CommandOption{
+		{Type: discord.OptionString, Name: "symbol", Description: "The symbol, for example XYZUSDT", Required: true},
+		{Type: discord.OptionString, Name: "duration", Description: "For example 2h or 1d, until the program exits when not set"},
+	}},
+	{Name: "exclude", Description: "Exclude a coin from being quarantined", Options: []discord.CommandOption{
+		{Type: discord.OptionString, Name: "symbol", Description: "The symbol, for example XYZUSDT", Required: true},
+		{Type: discord.OptionString, Name: "duration", Description: "For example 2h or 1d", Required: true},
+	}},
+}
+
+// controlCommands the commands that require one of the roles.
+var controlCommands = []string{"run", "quarantine", "permit", "exclude"}
+
+// Bot answers the slash commands sent to the interactions endpoint.
+//
+//	/status                        running state, last run and the lists
+//	/swing                         market swing of the last run
+//	/run                           start a run now
+//	/quarantine SYMBOL [duration]  quarantine in WickHunter and add to the blacklist
+//	/permit SYMBOL [duration]      permit in WickHunter and add to the exclude list
+//	/exclude SYMBOL duration       add to the exclude list
+type Bot struct {
+	AutoCoins *autocoins.AutoCoins
+	PublicKey ed25519.PublicKey
+	Roles     []string // Roles the ids of the roles that can use the control commands.
+	server    *http.Server
+}
+
+// New creates the bot, the public key is the hex encoded public key of the Discord application.
+func New(a *autocoins.AutoCoins, address string, publicKey string, roles []string) (*Bot, error) {
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bot{
+		AutoCoins: a,
+		PublicKey: key,
+		Roles:     roles,
+	}
+	b.server = &http.Server{
+		Addr:    address,
+		Handler: b.Handler(),
+	}
+	return b, nil
+}
+
+// ParsePublicKey decodes the hex encoded public key of a Discord application.
+func ParsePublicKey(value string) (ed25519.PublicKey, error) {
+	key, err := hex.DecodeString(value)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+func (b *Bot) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, b.handleInteraction)
+	return mux
+}
+
+// Start listens in the background until Stop is called.
+func (b *Bot) Start() {
+	logger.Infof("Discord interactions endpoint listening on http://%s%s\n", b.server.Addr, Path)
+	go func() {
+		if err := b.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("Discord bot: %s\n", err.Error())
+		}
+	}()
+}
+
+func (b *Bot) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	b.server.Shutdown(ctx)
+}
+
+func (b *Bot) handleInteraction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	// Discord also sends requests with an invalid signature to check the endpoint.
+	if !discord.VerifyInteraction(b.PublicKey, r.Header.Get("X-Signature-Ed25519"), r.Header.Get("X-Signature-Timestamp"), body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var interaction discord.Interaction
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var response discord.InteractionResponse
+	switch interaction.Type {
+	case discord.InteractionPing:
+		response = discord.InteractionResponse{Type: discord.ResponsePong}
+	case discord.InteractionApplicationCommand:
+		response = b.command(&interaction)
+	default:
+		http.Error(w, "unsupported interaction type", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Discord bot write: %s\n", err.Error())
+	}
+}
+
+// command runs the slash command and returns the message to respond with.
+func (b *Bot) command(i *discord.Interaction) discord.InteractionResponse {
+	name := i.Data.Name
+	if autocoins.ContainsString(controlCommands, name) {
+		if !b.authorized(i) {
+			logger.Warnf("Discord: user %s is not allowed to use /%s\n", i.UserID(), name)
+			return reply("You are not allowed to use this command.", true)
+		}
+		logger.Infof("Discord: user %s used /%s %s\n", i.UserID(), name, i.Data.Option("symbol"))
+	}
+
+	switch name {
+	case "status":
+		return reply(b.status(), false)
+	case "swing":
+		return reply(b.swing(), false)
+	case "run":
+		b.AutoCoins.TriggerRun()
+		return reply("Run triggered.", false)
+	case "quarantine", "permit", "exclude":
+		message, err := b.setSymbol(name, i.Data.Option("symbol"), i.Data.Option("duration"))
+		if err != nil {
+			return reply(err.Error(), true)
+		}
+		return reply(message, false)
+	}
+	return reply(fmt.Sprintf("Unknown command /%s.", name), true)
+}
+
+// authorized returns true when the member has one of the roles, the control commands can not be used in a direct message.
+func (b *Bot) authorized(i *discord.Interaction) bool {
+	if i.Member == nil {
+		return false
+	}
+	for _, role := range i.Member.Roles {
+		if autocoins.ContainsString(b.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bot) status() string {
+	a := b.AutoCoins
+	s := strings.Builder{}
+	state := "stopped"
+	if a.IsRunning() {
+		state = "running"
+	}
+	if a.IsPaused() {
+		state += ", paused"
+	}
+	fmt.Fprintf(&s, "**AutoCoins** %s\n", state)
+
+	run, ok := a.LastRun()
+	if !ok {
+		s.WriteString("No run finished yet.\n")
+	} else {
+		fmt.Fprintf(&s, "Last run: %s (%.0fs)\n", run.Time.UTC().Format("2006-01-02 15:04 MST"), run.Duration)
+		if run.Schedule != "" {
+			fmt.Fprintf(&s, "Schedule: %s\n", run.Schedule)
+		}
+		if run.Error != "" {
+			fmt.Fprintf(&s, "Error: %s\n", run.Error)
+		}
+		fmt.Fprintf(&s, "Permitted: %d, quarantined: %d\n", len(run.Lists.Permitted), len(run.Lists.Quarantined))
+		if len(run.Lists.QuarantinedNew) > 0 {
+			fmt.Fprintf(&s, "New quarantined: %s\n", strings.Join(run.Lists.QuarantinedNew, ", "))
+		}
+		if len(run.Lists.QuarantinedRemoved) > 0 {
+			fmt.Fprintf(&s, "Unquarantined: %s\n", strings.Join(run.Lists.QuarantinedRemoved, ", "))
+		}
+	}
+
+	for _, o := range a.Overrides() {
+		until := "until exit"
+		if !o.Until.IsZero() {
+			until = "until " + o.Until.UTC().Format("2006-01-02 15:04 MST")
+		}
+		fmt.Fprintf(&s, "Override: %s %s %s\n", o.Symbol, o.List, until)
+	}
+	return truncate(s.String())
+}
+
+func (b *Bot) swing() string {
+	run, ok := b.AutoCoins.LastRun()
+	if !ok || len(run.MarketSwings) == 0 {
+		return "No market swing yet."
+	}
+	s := strings.Builder{}
+	for _, m := range run.MarketSwings {
+		fmt.Fprintf(&s, "**Last %s - %s**\n", m.Timeframe, m.SwingMood)
+		fmt.Fprintf(&s, "%.0f%% Long | %d Coins | Avg %.2f%% | Max %.2f%% %s\n", m.Positive.Percent, m.Positive.CoinCount, m.Positive.Average, m.Positive.Max, m.Positive.MaxCoin)
+		fmt.Fprintf(&s, "%.0f%% Short | %d Coins | Avg %.2f%% | Max %.2f%% %s\n", m.Negative.Percent, m.Negative.CoinCount, m.Negative.Average, m.Negative.Max, m.Negative.MaxCoin)
+	}
+	return s.String()
+}
+
+// setSymbol quarantines, permits or excludes the symbol. Quarantine and permit are applied to WickHunter immediately,
+// the overrides keep the runs from undoing them.
+func (b *Bot) setSymbol(command string, symbol string, duration string) (string, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if !symbolPattern.MatchString(symbol) {
+		return "", fmt.Errorf("Invalid symbol '%s'.", symbol)
+	}
+	d, err := ParseDuration(duration)
+	if err != nil {
+		return "", err
+	}
+	if command == "exclude" && d == 0 {
+		return "", fmt.Errorf("No duration, use for example 2h or 1d.")
+	}
+
+	a := b.AutoCoins
+	until := "until the program exits"
+	if d > 0 {
+		until = "for " + duration
+	}
+	switch command {
+	case "quarantine":
+		a.RemoveOverride(symbol, autocoins.OverrideExclude)
+		if _, err := a.AddOverride(symbol, autocoins.OverrideBlackList, d); err != nil {
+			return "", err
+		}
+		if err := b.setTrading(symbol, false); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s quarantined and blacklisted %s.", symbol, until), nil
+	case "permit":
+		a.RemoveOverride(symbol, autocoins.OverrideBlackList)
+		if _, err := a.AddOverride(symbol, autocoins.OverrideExclude, d); err != nil {
+			return "", err
+		}
+		if err := b.setTrading(symbol, true); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s permitted and excluded %s.", symbol, until), nil
+	default:
+		if _, err := a.AddOverride(symbol, autocoins.OverrideExclude, d); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s excluded %s, it is not quarantined from the next run.", symbol, until), nil
+	}
+}
+
+func (b *Bot) setTrading(symbol string, enabled bool) error {
+	a := b.AutoCoins
+	if a.DisableWrite || a.BotAPI == nil {
+		return nil
+	}
+	if err := a.BotAPI.WithTimeout(botAPITimeout).SetSymbolTrading(symbol, enabled); err != nil {
+		logger.Errorf("Discord: unable to update %s: %s\n", symbol, err.Error())
+		return fmt.Errorf("Unable to update %s in WickHunter, it is updated by the next run.", symbol)
+	}
+	return nil
+}
+
+// ParseDuration parses a duration like 30m, 2h or 1d (days), an empty value returns 0.
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || days < 1 {
+			return 0, fmt.Errorf("Invalid duration '%s', use for example 2h or 1d.", value)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("Invalid duration '%s', use for example 2h or 1d.", value)
+	}
+	return d, nil
+}
+
+func reply(content string, ephemeral bool) discord.InteractionResponse {
+	data := &discord.InteractionResponseData{Content: content}
+	if ephemeral {
+		data.Flags = discord.MessageFlagEphemeral
+	}
+	return discord.InteractionResponse{Type: discord.ResponseChannelMessageWithSource, Data: data}
+}
+
+// truncate keeps the content within the 2000 characters of a message.
+func truncate(content string) string {
+	runes := []rune(content)
+	if len(runes) <= 2000 {
+		return content
+	}
+	return string(runes[:1999]) + "…"
+}
@@ -0,0 +1,202 @@
+package discordbot
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/discord"
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
+)
+
+type testBot struct {
+	bot        *Bot
+	privateKey ed25519.PrivateKey
+	requests   []string // requests the requests received by the WickHunter stand-in.
+	status     int      // status (optional) the response status of the WickHunter stand-in.
+}
+
+func newTestBot(t *testing.T) (*testBot, func()) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err.Error())
+	}
+	tb := &testBot{privateKey: privateKey}
+	wickHunter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tb.requests = append(tb.requests, r.Method+" "+r.URL.Path)
+		if tb.status != 0 {
+			w.WriteHeader(tb.status)
+		}
+	}))
+
+	a := &autocoins.AutoCoins{BotAPI: wickhunter.NewAPI(wickHunter.URL)}
+	b, err := New(a, "127.0.0.1:0", hex.EncodeToString(publicKey), []string{"admin"})
+	if err != nil {
+		t.Fatalf("New returned error: %s", err.Error())
+	}
+	tb.bot = b
+	return tb, wickHunter.Close
+}
+
+// send signs and sends the interaction, returns the response.
+func (tb *testBot) send(t *testing.T, interaction discord.Interaction) (int, discord.InteractionResponse) {
+	body, _ := json.Marshal(interaction)
+	timestamp := "1622808000"
+	signature := ed25519.Sign(tb.privateKey, append([]byte(timestamp), body...))
+
+	r := httptest.NewRequest(http.MethodPost, Path, strings.NewReader(string(body)))
+	r.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	r.Header.Set("X-Signature-Timestamp", timestamp)
+	w := httptest.NewRecorder()
+	tb.bot.Handler().ServeHTTP(w, r)
+
+	var response discord.InteractionResponse
+	if w.Code == http.StatusOK {
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("invalid response: %s", err.Error())
+		}
+	}
+	return w.Code, response
+}
+
+func command(name string, roles []string, options map[string]string) discord.Interaction {
+	i := discord.Interaction{
+		Type:   discord.InteractionApplicationCommand,
+		Data:   discord.ApplicationCommandData{Name: name},
+		Member: &discord.Member{User: discord.User{ID: "1"}, Roles: roles},
+	}
+	for name, value := range options {
+		v, _ := json.Marshal(value)
+		i.Data.Options = append(i.Data.Options, discord.InteractionOption{Name: name, Type: discord.OptionString, Value: v})
+	}
+	return i
+}
+
+func TestSignature(t *testing.T) {
+	tb, stop := newTestBot(t)
+	defer stop()
+
+	if code, response := tb.send(t, discord.Interaction{Type: discord.InteractionPing}); code != http.StatusOK || response.Type != discord.ResponsePong {
+		t.Errorf("ping: expected pong got %d %+v", code, response)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, Path, strings.NewReader(`{"type": 1}`))
+	r.Header.Set("X-Signature-Ed25519", strings.Repeat("00", ed25519.SignatureSize))
+	r.Header.Set("X-Signature-Timestamp", "1622808000")
+	w := httptest.NewRecorder()
+	tb.bot.Handler().ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("invalid signature: expected %d got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestQuarantineAndPermit(t *testing.T) {
+	tb, stop := newTestBot(t)
+	defer stop()
+	a := tb.bot.AutoCoins
+
+	_, response := tb.send(t, command("quarantine", []string{"admin"}, map[string]string{"symbol": "xyzusdt"}))
+	if !strings.Contains(response.Data.Content, "XYZUSDT quarantined") {
+		t.Errorf("quarantine: invalid response %s", response.Data.Content)
+	}
+	overrides := a.Overrides()
+	if len(overrides) != 1 || overrides[0].List != autocoins.OverrideBlackList || !overrides[0].Until.IsZero() {
+		t.Errorf("quarantine: invalid overrides %+v", overrides)
+	}
+
+	tb.send(t, command("permit", []string{"admin"}, map[string]string{"symbol": "XYZUSDT", "duration": "1d"}))
+	overrides = a.Overrides()
+	if len(overrides) != 1 || overrides[0].List != autocoins.OverrideExclude || time.Until(overrides[0].Until) < 23*time.Hour {
+		t.Errorf("permit: invalid overrides %+v", overrides)
+	}
+
+	expected := []string{"PUT /symbols/xyzusdt/enable/false", "PUT /symbols/xyzusdt/enable/true"}
+	if strings.Join(tb.requests, "|") != strings.Join(expected, "|") {
+		t.Errorf("invalid WickHunter requests: expected %v got %v", expected, tb.requests)
+	}
+}
+
+func TestQuarantineWithoutRetries(t *testing.T) {
+	tb, stop := newTestBot(t)
+	defer stop()
+	tb.status = http.StatusServiceUnavailable
+
+	start := time.Now()
+	_, response := tb.send(t, command("quarantine", []string{"admin"}, map[string]string{"symbol": "XYZUSDT"}))
+	if elapsed := time.Since(start); elapsed > botAPITimeout {
+		t.Errorf("quarantine: expected a response within %s got %s", botAPITimeout, elapsed)
+	}
+	if !strings.Contains(response.Data.Content, "Unable to update XYZUSDT") {
+		t.Errorf("quarantine: invalid response %s", response.Data.Content)
+	}
+	if len(tb.requests) != 1 {
+		t.Errorf("invalid WickHunter requests: expected 1 got %v", tb.requests)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	tb, stop := newTestBot(t)
+	defer stop()
+
+	_, response := tb.send(t, command("quarantine", []string{"member"}, map[string]string{"symbol": "XYZUSDT"}))
+	if response.Data.Flags != discord.MessageFlagEphemeral || !strings.Contains(response.Data.Content, "not allowed") {
+		t.Errorf("expected the command to be denied: got %+v", response.Data)
+	}
+	if len(tb.requests) != 0 || len(tb.bot.AutoCoins.Overrides()) != 0 {
+		t.Errorf("a denied command changed the state")
+	}
+
+	_, response = tb.send(t, command("status", nil, nil))
+	if !strings.Contains(response.Data.Content, "No run finished yet") {
+		t.Errorf("status: invalid response %s", response.Data.Content)
+	}
+}
+
+func TestExclude(t *testing.T) {
+	tb, stop := newTestBot(t)
+	defer stop()
+
+	_, response := tb.send(t, command("exclude", []string{"admin"}, map[string]string{"symbol": "XYZUSDT"}))
+	if response.Data.Flags != discord.MessageFlagEphemeral {
+		t.Errorf("exclude without duration: expected an error got %s", response.Data.Content)
+	}
+	_, response = tb.send(t, command("exclude", []string{"admin"}, map[string]string{"symbol": "XYZ/../USDT", "duration": "2h"}))
+	if response.Data.Flags != discord.MessageFlagEphemeral {
+		t.Errorf("exclude invalid symbol: expected an error got %s", response.Data.Content)
+	}
+	tb.send(t, command("exclude", []string{"admin"}, map[string]string{"symbol": "XYZUSDT", "duration": "2h"}))
+	if overrides := tb.bot.AutoCoins.Overrides(); len(overrides) != 1 || overrides[0].List != autocoins.OverrideExclude {
+		t.Errorf("exclude: invalid overrides %+v", overrides)
+	}
+	if len(tb.requests) != 0 {
+		t.Errorf("exclude should not update WickHunter: got %v", tb.requests)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		err      bool
+	}{
+		{"", 0, false},
+		{"30m", 30 * time.Minute, false},
+		{"2h", 2 * time.Hour, false},
+		{"1d", 24 * time.Hour, false},
+		{"0d", 0, true},
+		{"-2h", 0, true},
+		{"soon", 0, true},
+	}
+	for _, test := range tests {
+		d, err := ParseDuration(test.value)
+		if (err != nil) != test.err || d != test.expected {
+			t.Errorf("ParseDuration(%s): expected %v (error %v) got %v (%v)", test.value, test.expected, test.err, d, err)
+		}
+	}
+}
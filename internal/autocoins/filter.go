@@ -2,16 +2,16 @@ package autocoins
 
 import (
 	"github.com/LompeBoer/go-autocoins/internal/autocoins/filters"
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 	"github.com/LompeBoer/go-autocoins/internal/pairslist"
 	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
 )
 
 type Filter interface {
-	KeepSymbol(binance.Symbol) bool
+	KeepSymbol(exchange.Symbol) bool
 }
 
-func (a *AutoCoins) createFilters(usedSymbols []wickhunter.Position, symbols []binance.Symbol, pairsList []pairslist.Pair) []Filter {
+func (a *AutoCoins) createFilters(usedSymbols []wickhunter.Position, symbols []exchange.Symbol, pairsList []pairslist.Pair) []Filter {
 	filterList := []Filter{}
 	// Check if symbol is present in the WickHunter Bot Instrument table.
 	if a.Settings.Filters.WickHunterDB {
@@ -39,10 +39,10 @@ func (a *AutoCoins) createFilters(usedSymbols []wickhunter.Position, symbols []b
 
 // filterSymbols filters out the symbols from the exchangeInfo that are not used in the local storage file.
 // It also checks the MarginAssets setting and filters out any symbol which uses a margin asset not in this list.
-func (a *AutoCoins) filterSymbols(usedSymbols []wickhunter.Position, symbols []binance.Symbol, pairsList []pairslist.Pair) ([]binance.Symbol, error) {
+func (a *AutoCoins) filterSymbols(usedSymbols []wickhunter.Position, symbols []exchange.Symbol, pairsList []pairslist.Pair) ([]exchange.Symbol, error) {
 	filters := a.createFilters(usedSymbols, symbols, pairsList)
 
-	keepSymbol := func(symbol binance.Symbol) bool {
+	keepSymbol := func(symbol exchange.Symbol) bool {
 		if ContainsStringSorted(a.Settings.Filters.ExcludeList, symbol.Name) {
 			return true
 		}
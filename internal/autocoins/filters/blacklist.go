@@ -1,12 +1,12 @@
 package filters
 
-import "github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+import "github.com/LompeBoer/go-autocoins/internal/exchange"
 
 type BlackListFilter struct {
 	BlackList []string
 }
 
-func (f *BlackListFilter) KeepSymbol(symbol binance.Symbol) bool {
+func (f *BlackListFilter) KeepSymbol(symbol exchange.Symbol) bool {
 	return !blackListContainsSymbol(f.BlackList, symbol.Name)
 }
 
@@ -3,7 +3,7 @@ package filters
 import (
 	"testing"
 
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 )
 
 func TestBlackListFilter(t *testing.T) {
@@ -13,7 +13,7 @@ func TestBlackListFilter(t *testing.T) {
 		BlackList: []string{symbolName},
 	}
 
-	symbol := binance.Symbol{Name: symbolName}
+	symbol := exchange.Symbol{Name: symbolName}
 	keep := filter.KeepSymbol(symbol)
 	if keep {
 		t.Errorf("blacklist filter invalid result: expected %v got %v", false, keep)
@@ -25,7 +25,7 @@ func TestBlackListFilterOther(t *testing.T) {
 		BlackList: []string{"INCLUDED"},
 	}
 
-	symbol := binance.Symbol{Name: "EXCLUDED"}
+	symbol := exchange.Symbol{Name: "EXCLUDED"}
 	keep := filter.KeepSymbol(symbol)
 	if !keep {
 		t.Errorf("blacklist filter 'other' invalid result: expected %v got %v", true, keep)
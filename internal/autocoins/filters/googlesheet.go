@@ -1,7 +1,7 @@
 package filters
 
 import (
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 	"github.com/LompeBoer/go-autocoins/internal/pairslist"
 )
 
@@ -11,7 +11,7 @@ type GoogleSheetFilter struct {
 	UseSafeList bool
 }
 
-func (f *GoogleSheetFilter) KeepSymbol(symbol binance.Symbol) bool {
+func (f *GoogleSheetFilter) KeepSymbol(symbol exchange.Symbol) bool {
 	if whiteListContainsSymbol(f.WhiteList, symbol.Name) {
 		return true
 	}
@@ -3,7 +3,7 @@ package filters
 import (
 	"testing"
 
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 	"github.com/LompeBoer/go-autocoins/internal/pairslist"
 )
 
@@ -22,7 +22,7 @@ func TestGoogleSheetFilterPermitted(t *testing.T) {
 		},
 	}
 
-	symbol := binance.Symbol{Name: symbolName}
+	symbol := exchange.Symbol{Name: symbolName}
 	keep := filter.KeepSymbol(symbol)
 	if !keep {
 		t.Errorf("google sheet filter 'permitted' invalid result: expected %v got %v", false, keep)
@@ -44,7 +44,7 @@ func TestGoogleSheetFilterSafe(t *testing.T) {
 		},
 	}
 
-	symbol := binance.Symbol{Name: symbolName}
+	symbol := exchange.Symbol{Name: symbolName}
 	keep := filter.KeepSymbol(symbol)
 	if !keep {
 		t.Errorf("google sheet filter 'safe' invalid result: expected %v got %v", false, keep)
@@ -66,7 +66,7 @@ func TestGoogleSheetFilterBlock(t *testing.T) {
 		},
 	}
 
-	symbol := binance.Symbol{Name: symbolName}
+	symbol := exchange.Symbol{Name: symbolName}
 	keep := filter.KeepSymbol(symbol)
 	if keep {
 		t.Errorf("google sheet filter 'block' invalid result: expected %v got %v", true, keep)
@@ -86,7 +86,7 @@ func TestGoogleSheetFilterOther(t *testing.T) {
 		},
 	}
 
-	symbol := binance.Symbol{Name: "EXCLUDED"}
+	symbol := exchange.Symbol{Name: "EXCLUDED"}
 	keep := filter.KeepSymbol(symbol)
 	if keep {
 		t.Errorf("google sheet filter 'other' invalid result: expected %v got %v", true, keep)
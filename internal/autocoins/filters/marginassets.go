@@ -1,12 +1,12 @@
 package filters
 
-import "github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+import "github.com/LompeBoer/go-autocoins/internal/exchange"
 
 type MarginAssetsFilter struct {
 	MarginAssets []string
 }
 
-func (f *MarginAssetsFilter) KeepSymbol(symbol binance.Symbol) bool {
+func (f *MarginAssetsFilter) KeepSymbol(symbol exchange.Symbol) bool {
 	return marginAssetsContainsSymbol(f.MarginAssets, symbol.MarginAsset)
 }
 
@@ -3,7 +3,7 @@ package filters
 import (
 	"testing"
 
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 )
 
 func TestMarginAssetsFilter(t *testing.T) {
@@ -13,7 +13,7 @@ func TestMarginAssetsFilter(t *testing.T) {
 		MarginAssets: []string{marginAsset},
 	}
 
-	symbol := binance.Symbol{Name: "TESTNAME", MarginAsset: marginAsset}
+	symbol := exchange.Symbol{Name: "TESTNAME", MarginAsset: marginAsset}
 	keep := filter.KeepSymbol(symbol)
 	if !keep {
 		t.Errorf("marginassets filter invalid result: expected %v got %v", true, keep)
@@ -25,7 +25,7 @@ func TestMarginAssetsFilterOther(t *testing.T) {
 		MarginAssets: []string{"INCLUDED"},
 	}
 
-	symbol := binance.Symbol{Name: "TESTNAME", MarginAsset: "EXCLUDED"}
+	symbol := exchange.Symbol{Name: "TESTNAME", MarginAsset: "EXCLUDED"}
 	keep := filter.KeepSymbol(symbol)
 	if keep {
 		t.Errorf("marginassets filter 'other' invalid result: expected %v got %v", false, keep)
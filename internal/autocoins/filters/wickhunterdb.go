@@ -1,7 +1,7 @@
 package filters
 
 import (
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
 )
 
@@ -9,7 +9,7 @@ type WickHunterDBFilter struct {
 	Positions []wickhunter.Position
 }
 
-func (f *WickHunterDBFilter) KeepSymbol(symbol binance.Symbol) bool {
+func (f *WickHunterDBFilter) KeepSymbol(symbol exchange.Symbol) bool {
 	return positionContainsSymbol(f.Positions, symbol.Name)
 }
 
@@ -3,7 +3,7 @@ package filters
 import (
 	"testing"
 
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
 )
 
@@ -14,7 +14,7 @@ func TestWickHunterDBFilter(t *testing.T) {
 		Positions: []wickhunter.Position{{Symbol: symbolName}},
 	}
 
-	symbol := binance.Symbol{Name: symbolName}
+	symbol := exchange.Symbol{Name: symbolName}
 	keep := filter.KeepSymbol(symbol)
 	if !keep {
 		t.Errorf("wickhunterdb filter invalid result: expected %v got %v", true, keep)
@@ -26,7 +26,7 @@ func TestWickHunterDBFilterOther(t *testing.T) {
 		Positions: []wickhunter.Position{{Symbol: "INCLUDED"}},
 	}
 
-	symbol := binance.Symbol{Name: "EXCLUDED"}
+	symbol := exchange.Symbol{Name: "EXCLUDED"}
 	keep := filter.KeepSymbol(symbol)
 	if keep {
 		t.Errorf("wickhunterdb filter invalid result: expected %v got %v", false, keep)
@@ -0,0 +1,193 @@
+package autocoins
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/database/autocoinsdb"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// HistoryRecord the recorded decision for a symbol in a run.
+type HistoryRecord struct {
+	RunID       int64
+	Time        time.Time
+	Symbol      string
+	Quarantined bool
+	APIFailed   bool
+	Held        bool
+	Open        bool // Open failed the rules but kept trading because of an open position.
+	Excluded    bool // Excluded failed the rules but kept trading because it is excluded.
+	Values      SymbolDataValues
+	Results     []RuleResult
+}
+
+// recordRun stores the run with the values and rule results of all the symbols in the history.
+// The decision of a symbol is taken from the lists, a failed run is stored with the error and without symbols.
+func (a *AutoCoins) recordRun(objects []SymbolDataObject, lists SymbolLists, startTime time.Time, weight exchange.Weight, runErr error) {
+	if a.StateDB == nil || !a.Settings.History.Enabled {
+		return
+	}
+
+	listsJSON, err := json.Marshal(lists)
+	if err != nil {
+		logger.Errorf("unable to record run: %s\n", err.Error())
+		return
+	}
+	symbols := make([]autocoinsdb.RunSymbol, 0, len(objects))
+	for _, object := range objects {
+		values, err := json.Marshal(object.Values)
+		if err != nil {
+			logger.Errorf("unable to record run: %s\n", err.Error())
+			return
+		}
+		results, err := json.Marshal(object.Results)
+		if err != nil {
+			logger.Errorf("unable to record run: %s\n", err.Error())
+			return
+		}
+		name := object.Symbol.Name
+		symbols = append(symbols, autocoinsdb.RunSymbol{
+			Symbol:      name,
+			Quarantined: ContainsString(lists.Quarantined, name),
+			APIFailed:   object.APIFailed,
+			Held:        object.Held,
+			Open:        ContainsString(lists.QuarantinedSkipped, name),
+			Excluded:    ContainsString(lists.QuarantinedExcluded, name),
+			Values:      string(values),
+			Results:     string(results),
+		})
+	}
+
+	run := autocoinsdb.Run{
+		Time:        startTime,
+		Duration:    time.Since(startTime),
+		WeightUsed:  weight.Used,
+		WeightLimit: weight.Limit,
+		Lists:       string(listsJSON),
+	}
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+	if _, err := a.StateDB.InsertRun(run, symbols); err != nil {
+		logger.Errorf("unable to record run: %s\n", err.Error())
+	}
+
+	if days := a.Settings.History.RetentionDays; days > 0 {
+		if err := a.StateDB.DeleteRunsBefore(startTime.AddDate(0, 0, -days)); err != nil {
+			logger.Errorf("unable to remove old runs: %s\n", err.Error())
+		}
+	}
+}
+
+// SymbolAt returns the decision for the symbol in the last run at or before the given time.
+func (a *AutoCoins) SymbolAt(symbol string, at time.Time) (HistoryRecord, error) {
+	if a.StateDB == nil {
+		return HistoryRecord{}, fmt.Errorf("no history database")
+	}
+	item, err := a.StateDB.SelectSymbolAt(symbol, at)
+	if err != nil {
+		return HistoryRecord{}, err
+	}
+	return toHistoryRecord(item)
+}
+
+// SymbolHistory returns the decisions for the symbol between from and to, oldest first.
+func (a *AutoCoins) SymbolHistory(symbol string, from time.Time, to time.Time) ([]HistoryRecord, error) {
+	if a.StateDB == nil {
+		return nil, fmt.Errorf("no history database")
+	}
+	items, err := a.StateDB.SelectSymbolHistory(symbol, from, to)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]HistoryRecord, 0, len(items))
+	for _, item := range items {
+		record, err := toHistoryRecord(item)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Reason returns why the symbol was quarantined or permitted.
+func (r *HistoryRecord) Reason() string {
+	if r.APIFailed {
+		return "failed to retrieve the data"
+	}
+	failed := []RuleResult{}
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) == 0 {
+		if r.Held {
+			return "held in quarantine"
+		}
+		return "passed all rules"
+	}
+	reason := ""
+	for i, result := range failed {
+		if i > 0 {
+			reason += ", "
+		}
+		reason += result.Reason
+	}
+	if r.Open {
+		reason += " (not quarantined, open position)"
+	} else if r.Excluded {
+		reason += " (not quarantined, excluded)"
+	}
+	return reason
+}
+
+func toHistoryRecord(item autocoinsdb.RunSymbol) (HistoryRecord, error) {
+	record := HistoryRecord{
+		RunID:       item.RunID,
+		Time:        item.Time,
+		Symbol:      item.Symbol,
+		Quarantined: item.Quarantined,
+		APIFailed:   item.APIFailed,
+		Held:        item.Held,
+		Open:        item.Open,
+		Excluded:    item.Excluded,
+	}
+	if err := json.Unmarshal([]byte(item.Values), &record.Values); err != nil {
+		return HistoryRecord{}, err
+	}
+	if err := json.Unmarshal([]byte(item.Results), &record.Results); err != nil {
+		return HistoryRecord{}, err
+	}
+	return record, nil
+}
+
+// FailedRuns returns the number of failed runs and the total number of runs between from and to.
+func (a *AutoCoins) FailedRuns(from time.Time, to time.Time) (int, int, error) {
+	if a.StateDB == nil {
+		return 0, 0, fmt.Errorf("no history database")
+	}
+	runs, err := a.StateDB.SelectRuns(from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+	failed := 0
+	for _, run := range runs {
+		if run.Error != "" {
+			failed++
+		}
+	}
+	return failed, len(runs), nil
+}
+
+// QuarantinedCount returns the number of runs the symbol was quarantined and the number of runs it was processed between from and to.
+func (a *AutoCoins) QuarantinedCount(symbol string, from time.Time, to time.Time) (int, int, error) {
+	if a.StateDB == nil {
+		return 0, 0, fmt.Errorf("no history database")
+	}
+	return a.StateDB.CountQuarantined(symbol, from, to)
+}
@@ -0,0 +1,80 @@
+package autocoins
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/database/autocoinsdb"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func TestRunHistory(t *testing.T) {
+	db := autocoinsdb.New(filepath.Join(t.TempDir(), "autocoins.db"))
+	defer db.Close()
+	if err := db.CreateHistoryTables(); err != nil {
+		t.Fatalf("unable to create tables: %s", err.Error())
+	}
+	a := AutoCoins{StateDB: db, Settings: Settings{History: SettingsHistory{Enabled: true}}}
+
+	start := time.Date(2021, 6, 1, 3, 0, 0, 0, time.UTC)
+	for i, percent := range []float64{6, 2, 7, 8} {
+		object := SymbolDataObject{
+			Symbol: exchange.Symbol{Name: "XYZUSDT"},
+			Values: SymbolDataValues{Percent1Hour: []float64{percent}},
+			rules:  createRules([]string{"1hr"}, &SettingsAutoCoins{Max1hrPercent: 5}),
+		}
+		object.checkRules()
+		lists := SymbolLists{}
+		if object.ShouldQuarantine() {
+			lists.Quarantined = []string{"XYZUSDT"}
+		}
+		// The last run has an open position, the symbol keeps trading.
+		if i == 3 {
+			lists = SymbolLists{QuarantinedSkipped: []string{"XYZUSDT"}, Permitted: []string{"XYZUSDT"}}
+		}
+		a.recordRun([]SymbolDataObject{object}, lists, start.Add(time.Duration(i)*10*time.Minute), exchange.Weight{Used: 10, Limit: 2400}, nil)
+	}
+	a.recordRun(nil, SymbolLists{}, start.Add(40*time.Minute), exchange.Weight{}, errors.New("unable to retrieve enough data"))
+
+	record, err := a.SymbolAt("XYZUSDT", start.Add(15*time.Minute))
+	if err != nil {
+		t.Fatalf("SymbolAt returned error: %s", err.Error())
+	}
+	if record.Quarantined || record.Values.Percent1Hour[0] != 2 {
+		t.Errorf("invalid record at 03:15: expected permitted with 2%% got %v %v", record.Quarantined, record.Values.Percent1Hour)
+	}
+
+	record, err = a.SymbolAt("XYZUSDT", start.Add(25*time.Minute))
+	if err != nil {
+		t.Fatalf("SymbolAt returned error: %s", err.Error())
+	}
+	if !record.Quarantined || record.Reason() != "1hr change 7.00% exceeds 5.00%" {
+		t.Errorf("invalid record at 03:25: expected quarantined got %v '%s'", record.Quarantined, record.Reason())
+	}
+
+	quarantined, total, err := a.QuarantinedCount("XYZUSDT", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("QuarantinedCount returned error: %s", err.Error())
+	}
+	if quarantined != 2 || total != 4 {
+		t.Errorf("invalid quarantined count: expected %d/%d got %d/%d", 2, 4, quarantined, total)
+	}
+
+	record, err = a.SymbolAt("XYZUSDT", start.Add(35*time.Minute))
+	if err != nil {
+		t.Fatalf("SymbolAt returned error: %s", err.Error())
+	}
+	if record.Quarantined || !record.Open || record.Reason() != "1hr change 8.00% exceeds 5.00% (not quarantined, open position)" {
+		t.Errorf("invalid record at 03:35: expected permitted with an open position got %v '%s'", record.Quarantined, record.Reason())
+	}
+
+	failed, runs, err := a.FailedRuns(start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("FailedRuns returned error: %s", err.Error())
+	}
+	if failed != 1 || runs != 5 {
+		t.Errorf("invalid failed runs: expected %d/%d got %d/%d", 1, 5, failed, runs)
+	}
+}
@@ -0,0 +1,119 @@
+package autocoins
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/database/autocoinsdb"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// applyHysteresis keeps quarantined symbols quarantined until they are quarantined for the minimum duration
+// and passed the release thresholds for the required number of consecutive runs.
+// The quarantine state is stored in the StateDB (when set) so it survives a restart.
+func (a *AutoCoins) applyHysteresis(objects []SymbolDataObject, now time.Time) {
+	if a.quarantineStates == nil {
+		a.quarantineStates = a.loadQuarantineStates()
+	}
+	settings := a.Settings.Quarantine
+
+	for i := range objects {
+		object := &objects[i]
+		if object.APIFailed {
+			continue
+		}
+		name := object.Symbol.Name
+		state, quarantined := a.quarantineStates[name]
+
+		if object.ShouldQuarantine() {
+			if !quarantined {
+				state = autocoinsdb.QuarantineState{Symbol: name, Since: now}
+			}
+			state.CleanRuns = 0
+			a.quarantineStates[name] = state
+			continue
+		}
+		if !quarantined {
+			continue
+		}
+
+		if object.passesRelease(settings.ReleaseThresholds) {
+			state.CleanRuns++
+		} else {
+			state.CleanRuns = 0
+		}
+
+		minDuration := time.Duration(settings.MinMinutes) * time.Minute
+		if now.Sub(state.Since) >= minDuration && state.CleanRuns >= settings.CleanRuns {
+			delete(a.quarantineStates, name)
+			continue
+		}
+		a.quarantineStates[name] = state
+		object.Held = true
+		object.HeldReason = fmt.Sprintf("quarantined for %s, %d/%d clean runs", now.Sub(state.Since).Round(time.Minute), state.CleanRuns, settings.CleanRuns)
+	}
+
+	a.saveQuarantineStates()
+}
+
+// pruneQuarantineStates removes the quarantine state of the symbols that are no longer listed on the exchange.
+// Symbols that are only filtered (blacklist, schedule or override) keep their state.
+func (a *AutoCoins) pruneQuarantineStates(listed []exchange.Symbol) {
+	if a.quarantineStates == nil {
+		a.quarantineStates = a.loadQuarantineStates()
+	}
+	names := make(map[string]bool, len(listed))
+	for _, symbol := range listed {
+		names[symbol.Name] = true
+	}
+	for name := range a.quarantineStates {
+		if !names[name] {
+			delete(a.quarantineStates, name)
+		}
+	}
+}
+
+// passesRelease returns true when all the rules pass using the release thresholds.
+func (s *SymbolDataObject) passesRelease(thresholds map[string]float64) bool {
+	if len(thresholds) == 0 {
+		return true
+	}
+	for _, rule := range withThresholds(s.rules, thresholds) {
+		if !rule.Check(s).Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *AutoCoins) loadQuarantineStates() map[string]autocoinsdb.QuarantineState {
+	states := map[string]autocoinsdb.QuarantineState{}
+	if a.StateDB == nil {
+		return states
+	}
+	items, err := a.StateDB.SelectQuarantineStates()
+	if err != nil {
+		logger.Errorf("unable to load quarantine state: %s\n", err.Error())
+		return states
+	}
+	for _, item := range items {
+		states[item.Symbol] = item
+	}
+	return states
+}
+
+func (a *AutoCoins) saveQuarantineStates() {
+	if a.StateDB == nil {
+		return
+	}
+	items := make([]autocoinsdb.QuarantineState, 0, len(a.quarantineStates))
+	for _, state := range a.quarantineStates {
+		items = append(items, state)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Symbol < items[j].Symbol })
+	if err := a.StateDB.ReplaceQuarantineStates(items); err != nil {
+		logger.Errorf("unable to save quarantine state: %s\n", err.Error())
+	}
+}
@@ -0,0 +1,99 @@
+package autocoins
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/database/autocoinsdb"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func TestApplyHysteresis(t *testing.T) {
+	settings := SettingsAutoCoins{Max1hrPercent: 5}
+	a := AutoCoins{Settings: Settings{
+		Quarantine: SettingsQuarantine{
+			MinMinutes:        30,
+			CleanRuns:         2,
+			ReleaseThresholds: map[string]float64{"1hr": 4},
+		},
+	}}
+	run := func(percent float64, now time.Time) SymbolDataObject {
+		objects := []SymbolDataObject{{
+			Symbol: exchange.Symbol{Name: "TEST"},
+			Values: SymbolDataValues{Percent1Hour: []float64{percent}},
+			rules:  createRules([]string{"1hr"}, &settings),
+		}}
+		objects[0].checkRules()
+		a.applyHysteresis(objects, now)
+		return objects[0]
+	}
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		percent    float64
+		minutes    int
+		quarantine bool
+	}{
+		{"quarantined", 6, 0, true},
+		{"below release threshold but too soon", 3, 10, true},
+		{"between release and max resets clean runs", 4.5, 20, true},
+		{"first clean run", 3, 40, true},
+		{"second clean run", 3, 50, false},
+		{"permitted", 4.5, 60, false},
+	}
+	for _, test := range tests {
+		object := run(test.percent, start.Add(time.Duration(test.minutes)*time.Minute))
+		if object.ShouldQuarantine() != test.quarantine {
+			t.Errorf("%s: expected quarantine %v got %v (%s)", test.name, test.quarantine, object.ShouldQuarantine(), object.HeldReason)
+		}
+	}
+}
+
+func TestPruneQuarantineStates(t *testing.T) {
+	db := autocoinsdb.New(filepath.Join(t.TempDir(), "autocoins.db"))
+	if err := db.CreateQuarantineTable(); err != nil {
+		t.Fatalf("CreateQuarantineTable returned error: %s", err.Error())
+	}
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := db.ReplaceQuarantineStates([]autocoinsdb.QuarantineState{
+		{Symbol: "DELISTED", Since: start},
+		{Symbol: "FILTERED", Since: start},
+		{Symbol: "TEST", Since: start},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceQuarantineStates returned error: %s", err.Error())
+	}
+
+	settings := SettingsAutoCoins{Max1hrPercent: 5}
+	a := AutoCoins{
+		Settings: Settings{Quarantine: SettingsQuarantine{MinMinutes: 30, CleanRuns: 2}},
+		StateDB:  db,
+	}
+	a.pruneQuarantineStates([]exchange.Symbol{{Name: "FILTERED"}, {Name: "TEST"}})
+
+	// FILTERED is listed but filtered from the run, it keeps its state.
+	objects := []SymbolDataObject{{
+		Symbol: exchange.Symbol{Name: "TEST"},
+		Values: SymbolDataValues{Percent1Hour: []float64{1}},
+		rules:  createRules([]string{"1hr"}, &settings),
+	}}
+	objects[0].checkRules()
+	a.applyHysteresis(objects, start.Add(10*time.Minute))
+
+	states, err := db.SelectQuarantineStates()
+	if err != nil {
+		t.Fatalf("SelectQuarantineStates returned error: %s", err.Error())
+	}
+	names := []string{}
+	for _, state := range states {
+		names = append(names, state.Symbol)
+	}
+	if len(names) != 2 || names[0] != "FILTERED" || names[1] != "TEST" {
+		t.Errorf("invalid quarantine states: expected [FILTERED TEST] got %v", names)
+	}
+	if _, ok := a.quarantineStates["DELISTED"]; ok {
+		t.Errorf("DELISTED: expected the state to be removed from memory")
+	}
+}
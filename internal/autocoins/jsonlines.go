@@ -0,0 +1,89 @@
+package autocoins
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// JSONLinesOutputWriter appends one JSON record per run, and optionally one per symbol, to a file.
+// Every record is written on a single line with a "type" field: run, symbol or error.
+type JSONLinesOutputWriter struct {
+	Writer  io.Writer // Writer usually a rotate.File.
+	Symbols bool      // Symbols also write a record for every symbol.
+}
+
+type jsonLinesRun struct {
+	Type         string        `json:"type"`
+	Time         time.Time     `json:"time"`
+	Duration     float64       `json:"durationSeconds"`
+	Schedule     string        `json:"schedule,omitempty"`
+	Permitted    int           `json:"permitted"`
+	Quarantined  int           `json:"quarantined"`
+	Failed       int           `json:"failed"`
+	Lists        SymbolLists   `json:"lists"`
+	MarketSwings []MarketSwing `json:"marketSwings"`
+}
+
+type jsonLinesSymbol struct {
+	Type        string    `json:"type"`
+	RunTime     time.Time `json:"runTime"`
+	Quarantined bool      `json:"quarantined"`
+	*SymbolDataObject
+}
+
+type jsonLinesError struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+func (w *JSONLinesOutputWriter) WriteResult(result *RunResult) error {
+	records := []interface{}{
+		jsonLinesRun{
+			Type:         "run",
+			Time:         result.Time,
+			Duration:     result.Duration.Seconds(),
+			Schedule:     result.Schedule,
+			Permitted:    len(result.Lists.Permitted),
+			Quarantined:  len(result.Lists.Quarantined),
+			Failed:       len(result.Lists.FailedToProcess),
+			Lists:        result.Lists,
+			MarketSwings: result.MarketSwings,
+		},
+	}
+	if w.Symbols {
+		for i := range result.Objects {
+			object := &result.Objects[i]
+			records = append(records, jsonLinesSymbol{
+				Type:             "symbol",
+				RunTime:          result.Time,
+				Quarantined:      !object.APIFailed && object.ShouldQuarantine(),
+				SymbolDataObject: object,
+			})
+		}
+	}
+	return w.write(records...)
+}
+
+func (w *JSONLinesOutputWriter) WriteError(message string) error {
+	return w.write(jsonLinesError{
+		Type:    "error",
+		Time:    time.Now(),
+		Message: message,
+	})
+}
+
+// write encodes the records and writes them at once so a run is not split over two files.
+func (w *JSONLinesOutputWriter) write(records ...interface{}) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	}
+	_, err := w.Writer.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,48 @@
+package autocoins
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func TestJSONLinesOutputWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &JSONLinesOutputWriter{Writer: &buf, Symbols: true}
+	result := &RunResult{
+		Time:     time.Date(2021, 6, 4, 12, 0, 0, 0, time.UTC),
+		Duration: 3 * time.Second,
+		Schedule: "night",
+		Objects: []SymbolDataObject{
+			{Symbol: exchange.Symbol{Name: "AAAUSDT"}, Results: []RuleResult{{Rule: "1hr", Passed: false}}},
+			{Symbol: exchange.Symbol{Name: "BBBUSDT"}, Results: []RuleResult{{Rule: "1hr", Passed: true}}},
+		},
+		Lists: SymbolLists{Permitted: []string{"BBBUSDT"}, Quarantined: []string{"AAAUSDT"}},
+	}
+	if err := w.WriteResult(result); err != nil {
+		t.Fatalf("WriteResult returned error: %s", err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("invalid number of records: expected %d got %d", 3, len(lines))
+	}
+	var run map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &run); err != nil {
+		t.Fatalf("invalid run record: %s", err.Error())
+	}
+	if run["type"] != "run" || run["schedule"] != "night" || run["quarantined"] != 1.0 || run["durationSeconds"] != 3.0 {
+		t.Errorf("invalid run record: got %s", lines[0])
+	}
+	var symbol map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &symbol); err != nil {
+		t.Fatalf("invalid symbol record: %s", err.Error())
+	}
+	if symbol["type"] != "symbol" || symbol["quarantined"] != true || symbol["results"] == nil {
+		t.Errorf("invalid symbol record: got %s", lines[1])
+	}
+}
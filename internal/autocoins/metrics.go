@@ -0,0 +1,75 @@
+package autocoins
+
+import (
+	"errors"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/metrics"
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
+)
+
+// Metrics the Prometheus metrics of the runs.
+type Metrics struct {
+	RunDuration     *metrics.Histogram
+	Runs            *metrics.Counter
+	Symbols         *metrics.Gauge
+	WeightUsed      *metrics.Gauge
+	WeightLimit     *metrics.Gauge
+	RuleQuarantined *metrics.Gauge
+	MarketSwing     *metrics.Gauge
+	BotAPIErrors    *metrics.Counter
+}
+
+func NewMetrics(r *metrics.Registry) *Metrics {
+	return &Metrics{
+		RunDuration:     r.NewHistogram("autocoins_run_duration_seconds", "Duration of a run.", []float64{1, 2, 5, 10, 20, 30, 60, 120, 300}),
+		Runs:            r.NewCounter("autocoins_runs_total", "Number of runs by result (success or failure).", "result"),
+		Symbols:         r.NewGauge("autocoins_symbols", "Number of symbols in the last run by list (permitted, quarantined or failed).", "list"),
+		WeightUsed:      r.NewGauge("autocoins_exchange_weight_used", "Exchange API weight used."),
+		WeightLimit:     r.NewGauge("autocoins_exchange_weight_limit", "Exchange API weight limit."),
+		RuleQuarantined: r.NewGauge("autocoins_rule_quarantined_symbols", "Number of symbols that did not pass the rule in the last run.", "rule"),
+		MarketSwing:     r.NewGauge("autocoins_market_swing_percent", "Market swing of the last run by timeframe, positive is bullish.", "timeframe"),
+		BotAPIErrors:    r.NewCounter("autocoins_bot_api_errors_total", "Number of symbols that failed to update in the WickHunter API."),
+	}
+}
+
+// observeRun updates the metrics with the result of a run.
+func (m *Metrics) observeRun(objects []SymbolDataObject, lists SymbolLists, duration time.Duration, err error, weight exchange.Weight) {
+	m.RunDuration.Observe(duration.Seconds())
+	m.WeightUsed.Set(float64(weight.Used))
+	m.WeightLimit.Set(float64(weight.Limit))
+	if err != nil {
+		m.Runs.Inc("failure")
+		return
+	}
+	m.Runs.Inc("success")
+
+	m.Symbols.Set(float64(len(lists.Permitted)), "permitted")
+	m.Symbols.Set(float64(len(lists.Quarantined)), "quarantined")
+	m.Symbols.Set(float64(len(lists.FailedToProcess)), "failed")
+
+	counts := map[string]int{}
+	for _, object := range objects {
+		for _, result := range object.FailedRules() {
+			counts[result.Rule]++
+		}
+	}
+	m.RuleQuarantined.Reset()
+	for rule, count := range counts {
+		m.RuleQuarantined.Set(float64(count), rule)
+	}
+	for _, swing := range CalculateMarketSwing(objects) {
+		m.MarketSwing.Set(swing.Swing, swing.Timeframe)
+	}
+}
+
+// observeBotAPIError counts the symbols that failed to update.
+func (m *Metrics) observeBotAPIError(err error) {
+	var updateError *wickhunter.UpdateError
+	if errors.As(err, &updateError) {
+		m.BotAPIErrors.Add(float64(len(updateError.Symbols)))
+		return
+	}
+	m.BotAPIErrors.Inc()
+}
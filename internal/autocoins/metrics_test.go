@@ -0,0 +1,95 @@
+package autocoins
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/metrics"
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter/wickhuntertest"
+)
+
+func TestObserveRun(t *testing.T) {
+	r := metrics.NewRegistry()
+	m := NewMetrics(r)
+
+	objects := []SymbolDataObject{
+		{
+			Symbol:  exchange.Symbol{Name: "AAA"},
+			Values:  SymbolDataValues{Percent1Hour: []float64{6}, Percent4Hour: 8},
+			Results: []RuleResult{{Rule: "1hr", Passed: false}, {Rule: "4hr", Passed: false}},
+		},
+		{
+			Symbol:  exchange.Symbol{Name: "BBB"},
+			Values:  SymbolDataValues{Percent1Hour: []float64{-6}},
+			Results: []RuleResult{{Rule: "1hr", Passed: false}, {Rule: "4hr", Passed: true}},
+		},
+	}
+	lists := SymbolLists{Quarantined: []string{"AAA", "BBB"}}
+	m.observeRun(objects, lists, 3*time.Second, nil, exchange.Weight{Used: 100, Limit: 2400})
+	m.observeRun(nil, SymbolLists{}, time.Second, errors.New("failed"), exchange.Weight{})
+	m.observeBotAPIError(&wickhunter.UpdateError{Symbols: []string{"AAA", "BBB"}, Total: 10})
+
+	var b bytes.Buffer
+	r.Write(&b)
+	for _, line := range []string{
+		`autocoins_runs_total{result="failure"} 1`,
+		`autocoins_runs_total{result="success"} 1`,
+		`autocoins_symbols{list="quarantined"} 2`,
+		`autocoins_rule_quarantined_symbols{rule="1hr"} 2`,
+		`autocoins_rule_quarantined_symbols{rule="4hr"} 1`,
+		`autocoins_market_swing_percent{timeframe="1hr"} 0`,
+		`autocoins_bot_api_errors_total 2`,
+		`autocoins_run_duration_seconds_count 2`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("expected '%s' in:\n%s", line, b.String())
+		}
+	}
+}
+
+func TestRunLoopCountsBotAPIErrors(t *testing.T) {
+	wickHunter := wickhuntertest.New()
+	wickHunter.AddSymbol(wickhunter.SymbolSettings{Symbol: "AAAUSDT"})
+	wickHunter.AddSymbol(wickhunter.SymbolSettings{Symbol: "BBBUSDT"})
+	wickHunter.FailSymbol("BBBUSDT")
+	server := wickHunter.Start()
+	defer server.Close()
+
+	storage := filepath.Join(t.TempDir(), "storage.sqlite")
+	if err := os.WriteFile(storage, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	onboard := time.Now().AddDate(0, -2, 0)
+	e := &stubExchange{
+		symbols: []exchange.Symbol{{Name: "AAAUSDT", OnboardDate: onboard}, {Name: "BBBUSDT", OnboardDate: onboard}},
+		tickers: []exchange.Ticker{{Symbol: "AAAUSDT"}, {Symbol: "BBBUSDT"}},
+		kline1m: flatKlines(240, 10),
+		kline1M: []exchange.Kline{{Open: 20, High: 20}},
+	}
+	r := metrics.NewRegistry()
+	a := newStubAutoCoins(e)
+	a.BotAPI = wickhunter.NewAPI(server.URL)
+	a.BotAPI.RetryDelay = 0
+	a.StorageFilename = storage
+	a.MaxFailedSymbolsPercentage = 0.1
+	a.Metrics = NewMetrics(r)
+
+	a.RunLoop()
+
+	var b bytes.Buffer
+	r.Write(&b)
+	if !strings.Contains(b.String(), "autocoins_bot_api_errors_total 1\n") {
+		t.Errorf("expected one WickHunter API error in:\n%s", b.String())
+	}
+	if settings, _ := wickHunter.Symbol("AAAUSDT"); !settings.Permitted {
+		t.Errorf("AAAUSDT: expected permitted in WickHunter")
+	}
+}
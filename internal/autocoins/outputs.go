@@ -0,0 +1,163 @@
+package autocoins
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/slack"
+)
+
+// SlackOutputWriter sends output to a Slack incoming webhook using Block Kit.
+type SlackOutputWriter struct {
+	Version        string
+	MentionOnError bool
+	WebHook        slack.WebHook
+}
+
+func (w *SlackOutputWriter) WriteResult(result *RunResult) error {
+	marketSwings, q := result.MarketSwings, result.Messages
+	e := slack.Escape
+	msg := slack.Message{
+		Text: "AutoCoins MarketSwing report",
+		Blocks: []slack.Block{
+			slack.Header("AutoCoins MarketSwing report"),
+			slack.Context(e(fmt.Sprintf("Generated %s (using v%s)", result.Time.Format("2006-01-02 15:04"), w.Version))),
+		},
+	}
+	if result.Schedule != "" {
+		msg.Blocks = append(msg.Blocks, slack.Context(e("Schedule: "+result.Schedule)))
+	}
+
+	for _, market := range marketSwings {
+		valueLong := fmt.Sprintf("%.0f%% Long | %d Coins | Avg %.2f%% | Max %.2f%% %s", market.Positive.Percent, market.Positive.CoinCount, market.Positive.Average, market.Positive.Max, market.Positive.MaxCoin)
+		valueShort := fmt.Sprintf("%.0f%% Short | %d Coins | Avg %.2f%% | Max %.2f%% %s", market.Negative.Percent, market.Negative.CoinCount, market.Negative.Average, market.Negative.Max, market.Negative.MaxCoin)
+		name := fmt.Sprintf("Last %s - %s", market.Timeframe, market.SwingMood)
+		msg.Blocks = append(msg.Blocks, slack.Section(fmt.Sprintf("*%s*\n%s\n%s", e(name), e(valueLong), e(valueShort))))
+	}
+	msg.Blocks = append(msg.Blocks, slack.Divider())
+
+	list := func(name string, value string, always bool) {
+		if value == "" && !always {
+			return
+		}
+		if value == "" {
+			value = "-"
+		}
+		msg.Blocks = append(msg.Blocks, slack.SplitSection(name, e(value), ", ")...)
+	}
+	list("New quarantined", q.NewQuarantined, false)
+	list("Quarantined", q.Quarantined, true)
+	list("Unquarantined", q.Unquarantined, false)
+	list("Open positions - not quarantined", q.OpenPositions, false)
+	list("Excluded - not quarantined", q.Excluded, false)
+	list("Failed to process", q.Failed, false)
+
+	return w.WebHook.SendMessage(msg)
+}
+
+func (w *SlackOutputWriter) WriteError(message string) error {
+	return w.WebHook.SendError(message, w.MentionOnError)
+}
+
+// WebhookData the data passed to the templates of a WebhookOutputWriter.
+type WebhookData struct {
+	Version string
+	Time    time.Time
+	Result  *RunResult // Result of the run, nil for an error.
+	Error   string     // Error the error message, empty for a result.
+}
+
+// WebhookOutputWriter sends a request with a body rendered by a template to a URL.
+type WebhookOutputWriter struct {
+	Name          string
+	Version       string
+	URL           string
+	Method        string
+	ContentType   string
+	Headers       map[string]string
+	Template      *template.Template
+	ErrorTemplate *template.Template // ErrorTemplate (optional) errors are not sent without a template.
+	client        http.Client
+}
+
+// templateFuncs the functions that can be used in the templates of a webhook.
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"time": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+}
+
+// NewWebhookOutputWriter creates the writer and parses the templates of the settings.
+func NewWebhookOutputWriter(s SettingsWebhook, version string) (*WebhookOutputWriter, error) {
+	w := &WebhookOutputWriter{
+		Name:        s.Name,
+		Version:     version,
+		URL:         s.URL,
+		Method:      strings.ToUpper(s.Method),
+		ContentType: s.ContentType,
+		Headers:     s.Headers,
+		client:      http.Client{Timeout: 10 * time.Second},
+	}
+	if w.Method == "" {
+		w.Method = http.MethodPost
+	}
+	if w.ContentType == "" {
+		w.ContentType = "application/json"
+	}
+	var err error
+	if w.Template, err = template.New(s.Name).Funcs(templateFuncs).Parse(s.Template); err != nil {
+		return nil, err
+	}
+	if s.ErrorTemplate != "" {
+		if w.ErrorTemplate, err = template.New(s.Name + "-error").Funcs(templateFuncs).Parse(s.ErrorTemplate); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (w *WebhookOutputWriter) WriteResult(result *RunResult) error {
+	return w.send(w.Template, WebhookData{Version: w.Version, Time: result.Time, Result: result})
+}
+
+func (w *WebhookOutputWriter) WriteError(message string) error {
+	if w.ErrorTemplate == nil {
+		return nil
+	}
+	return w.send(w.ErrorTemplate, WebhookData{Version: w.Version, Time: time.Now(), Error: message})
+}
+
+func (w *WebhookOutputWriter) send(t *template.Template, data WebhookData) error {
+	var body bytes.Buffer
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("webhook %s: %s", w.Name, err.Error())
+	}
+
+	req, err := http.NewRequest(w.Method, w.URL, &body)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %s", w.Name, err.Error())
+	}
+	req.Header.Set("Content-Type", w.ContentType)
+	for key, value := range w.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %s", w.Name, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to post webhook %s: %s", w.Name, resp.Status)
+	}
+	return nil
+}
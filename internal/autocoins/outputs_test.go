@@ -0,0 +1,131 @@
+package autocoins
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/slack"
+)
+
+// newRecordingServer returns a server that stores the received request bodies.
+func newRecordingServer(bodies *[]string, headers *[]http.Header) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		*bodies = append(*bodies, string(b))
+		if headers != nil {
+			*headers = append(*headers, r.Header)
+		}
+	}))
+}
+
+func testRunResult() *RunResult {
+	return &RunResult{
+		Time:         time.Date(2021, 6, 4, 12, 0, 0, 0, time.UTC),
+		Lists:        SymbolLists{Permitted: []string{"BBBUSDT"}, Quarantined: []string{"AAAUSDT"}},
+		MarketSwings: []MarketSwing{{Timeframe: "1hr", SwingMood: "20% Bullish"}},
+		Messages:     &QuarantineMessages{Quarantined: "AAAUSDT (1hr)"},
+	}
+}
+
+func TestSlackOutputWriter(t *testing.T) {
+	bodies := []string{}
+	server := newRecordingServer(&bodies, nil)
+	defer server.Close()
+
+	w := &SlackOutputWriter{Version: "1.0.0", MentionOnError: true, WebHook: slack.WebHook{URL: server.URL}}
+	if err := w.WriteResult(testRunResult()); err != nil {
+		t.Fatalf("WriteResult returned error: %s", err.Error())
+	}
+	if err := w.WriteError("failed <reason>"); err != nil {
+		t.Fatalf("WriteError returned error: %s", err.Error())
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("invalid number of requests: expected %d got %d", 2, len(bodies))
+	}
+
+	var msg slack.Message
+	if err := json.Unmarshal([]byte(bodies[0]), &msg); err != nil {
+		t.Fatalf("invalid message: %s", err.Error())
+	}
+	if msg.Blocks[0].Type != "header" {
+		t.Errorf("invalid first block: expected %s got %s", "header", msg.Blocks[0].Type)
+	}
+	if !strings.Contains(bodies[0], "*Quarantined*\\nAAAUSDT (1hr)") || strings.Contains(bodies[0], "Unquarantined") {
+		t.Errorf("invalid quarantined block: got %s", bodies[0])
+	}
+	if err := json.Unmarshal([]byte(bodies[1]), &msg); err != nil {
+		t.Fatalf("invalid error message: %s", err.Error())
+	}
+	if msg.Text != "<!here> failed &lt;reason&gt;" {
+		t.Errorf("invalid error message: expected %s got %s", "<!here> failed &lt;reason&gt;", msg.Text)
+	}
+}
+
+func TestWebhookOutputWriter(t *testing.T) {
+	bodies := []string{}
+	headers := []http.Header{}
+	server := newRecordingServer(&bodies, &headers)
+	defer server.Close()
+
+	w, err := NewWebhookOutputWriter(SettingsWebhook{
+		Name:          "ntfy",
+		URL:           server.URL,
+		ContentType:   "text/plain",
+		Headers:       map[string]string{"Title": "AutoCoins"},
+		Template:      `{{time "15:04" .Time}} quarantined: {{join .Result.Lists.Quarantined ", "}}{{range .Result.MarketSwings}} | {{.Timeframe}} {{.SwingMood}}{{end}}`,
+		ErrorTemplate: `{"error": {{json .Error}}}`,
+	}, "1.0.0")
+	if err != nil {
+		t.Fatalf("NewWebhookOutputWriter returned error: %s", err.Error())
+	}
+	if err := w.WriteResult(testRunResult()); err != nil {
+		t.Fatalf("WriteResult returned error: %s", err.Error())
+	}
+	if err := w.WriteError(`quote "x"`); err != nil {
+		t.Fatalf("WriteError returned error: %s", err.Error())
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("invalid number of requests: expected %d got %d", 2, len(bodies))
+	}
+	expected := "12:00 quarantined: AAAUSDT | 1hr 20% Bullish"
+	if bodies[0] != expected {
+		t.Errorf("invalid body: expected %s got %s", expected, bodies[0])
+	}
+	if headers[0].Get("Content-Type") != "text/plain" || headers[0].Get("Title") != "AutoCoins" {
+		t.Errorf("invalid headers: got %v", headers[0])
+	}
+	expected = `{"error": "quote \"x\""}`
+	if bodies[1] != expected {
+		t.Errorf("invalid error body: expected %s got %s", expected, bodies[1])
+	}
+}
+
+func TestWebhookOutputWriterInvalidTemplate(t *testing.T) {
+	_, err := NewWebhookOutputWriter(SettingsWebhook{Name: "x", URL: "http://localhost", Template: "{{.Result"}, "")
+	if err == nil {
+		t.Errorf("expected an error for an invalid template")
+	}
+}
+
+func TestWebhookOutputWriterNoErrorTemplate(t *testing.T) {
+	bodies := []string{}
+	server := newRecordingServer(&bodies, nil)
+	defer server.Close()
+
+	w, err := NewWebhookOutputWriter(SettingsWebhook{Name: "x", URL: server.URL, Template: "run"}, "")
+	if err != nil {
+		t.Fatalf("NewWebhookOutputWriter returned error: %s", err.Error())
+	}
+	if err := w.WriteError("failed"); err != nil {
+		t.Fatalf("WriteError returned error: %s", err.Error())
+	}
+	if len(bodies) != 0 {
+		t.Errorf("expected no request without an error template: got %d", len(bodies))
+	}
+}
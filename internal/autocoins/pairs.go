@@ -1,20 +1,19 @@
 package autocoins
 
 import (
-	"log"
-
+	"github.com/LompeBoer/go-autocoins/internal/logger"
 	"github.com/LompeBoer/go-autocoins/internal/pairslist"
 )
 
 func (a *AutoCoins) SetPairs(useSafe bool) {
 	list, err := pairslist.Read()
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatalf("Unable to read the pairs list: %s\n", err.Error())
 	}
 
 	positions, err := a.BotAPI.GetPositions()
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatalf("Unable to get the WickHunter positions: %s\n", err.Error())
 	}
 
 	permittedCoins := []string{}
@@ -35,11 +34,13 @@ func (a *AutoCoins) SetPairs(useSafe bool) {
 		}
 	}
 
-	a.BackupDatabase()
+	if err := a.BackupDatabase(); err != nil {
+		logger.Fatalf("Unable to backup the WickHunter database: %s\n", err.Error())
+	}
 	err = a.BotAPI.UpdatePermittedList(permittedCoins, quarantinedCoins)
 	if err != nil {
-		log.Fatal(err)
+		logger.Errorf("%s\n", err.Error())
 	}
 
-	log.Printf("Set %d pairs to permitted\n", len(permittedCoins))
+	logger.Infof("Set %d pairs to permitted\n", len(permittedCoins))
 }
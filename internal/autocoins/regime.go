@@ -0,0 +1,115 @@
+package autocoins
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+// ThresholdMultipliers the multipliers applied to the maximum price change of the 1hr, 4hr and 24hr rules.
+// A multiplier of 0 leaves the maximum unchanged.
+type ThresholdMultipliers struct {
+	Percent1Hour  float64 `json:"perc1hr"`
+	Percent4Hour  float64 `json:"perc4hr"`
+	Percent24Hour float64 `json:"perc24hr"`
+}
+
+// scaleMax returns the maximum multiplied by the multiplier.
+func scaleMax(max float64, multiplier float64) float64 {
+	if multiplier == 0 {
+		return max
+	}
+	return max * multiplier
+}
+
+// applyRegime scales the price change thresholds of every symbol to the market regime and checks the rules again.
+// The market swing of a timeframe selects the band, symbols that do not follow the correlation symbol get tighter limits.
+func (a *AutoCoins) applyRegime(objects []SymbolDataObject) {
+	settings := a.Settings.Regime
+	swings := CalculateMarketSwing(objects)
+	if swings[0].CountTotal == 0 {
+		// Without data the swing is -100%, that would select the widest band.
+		return
+	}
+	m1 := settings.bandMultiplier(swings[0].Swing)
+	m4 := settings.bandMultiplier(swings[1].Swing)
+	m24 := settings.bandMultiplier(swings[2].Swing)
+
+	for i := range objects {
+		object := &objects[i]
+		if object.APIFailed {
+			continue
+		}
+		decoupled := 1.0
+		if object.data.Shared != nil && object.data.Shared.CorrelationKlines != nil && object.Values.Correlation < settings.MinCorrelation {
+			decoupled = settings.DecoupledMultiplier
+		}
+		object.Multipliers = ThresholdMultipliers{
+			Percent1Hour:  m1 * decoupled,
+			Percent4Hour:  m4 * decoupled,
+			Percent24Hour: m24 * decoupled,
+		}
+		object.checkRules()
+	}
+}
+
+// bandMultiplier returns the multiplier of the band with the highest minimum swing that is reached.
+func (s *SettingsRegime) bandMultiplier(swing float64) float64 {
+	bands := append([]SettingsRegimeBand{}, s.Bands...)
+	sort.Slice(bands, func(i, j int) bool { return bands[i].MinSwing < bands[j].MinSwing })
+
+	multiplier := 1.0
+	for _, b := range bands {
+		if math.Abs(swing) >= b.MinSwing {
+			multiplier = b.Multiplier
+		}
+	}
+	return multiplier
+}
+
+// Correlation returns the Pearson correlation of the 1 minute returns of two kline series.
+// The klines are matched on their open time, returns 0 when there is not enough data.
+func Correlation(klines []exchange.Kline, other []exchange.Kline) float64 {
+	closes := make(map[time.Time]float64, len(other))
+	for _, k := range other {
+		closes[k.OpenTime] = k.Close
+	}
+
+	x := []float64{}
+	y := []float64{}
+	for i := 1; i < len(klines); i++ {
+		prev, ok1 := closes[klines[i-1].OpenTime]
+		cur, ok2 := closes[klines[i].OpenTime]
+		if !ok1 || !ok2 || prev == 0 || klines[i-1].Close == 0 {
+			continue
+		}
+		x = append(x, (klines[i].Close-klines[i-1].Close)/klines[i-1].Close)
+		y = append(y, (cur-prev)/prev)
+	}
+	if len(x) < 2 {
+		return 0
+	}
+
+	meanX, meanY := mean(x), mean(y)
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+func mean(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
@@ -0,0 +1,104 @@
+package autocoins
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func TestCorrelation(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := []exchange.Kline{}
+	inverse := []exchange.Kline{}
+	for i, c := range []float64{100, 102, 101, 104, 103, 107} {
+		openTime := start.Add(time.Duration(i) * time.Minute)
+		klines = append(klines, exchange.Kline{OpenTime: openTime, Close: c})
+		inverse = append(inverse, exchange.Kline{OpenTime: openTime, Close: 200 - c})
+	}
+
+	if c := Correlation(klines, klines); math.Abs(c-1) > 1e-9 {
+		t.Errorf("invalid correlation: expected %v got %v", 1.0, c)
+	}
+	if c := Correlation(klines, inverse); c > -0.9 {
+		t.Errorf("invalid inverse correlation: expected about %v got %v", -1.0, c)
+	}
+	if c := Correlation(klines, nil); c != 0 {
+		t.Errorf("invalid correlation without data: expected %v got %v", 0.0, c)
+	}
+}
+
+func TestApplyRegime(t *testing.T) {
+	settings := SettingsAutoCoins{Max1hrPercent: 5, Max4hrPercent: 5, Max24hrPercent: 10}
+	a := AutoCoins{Settings: Settings{
+		Regime: SettingsRegime{
+			Enabled:             true,
+			Bands:               []SettingsRegimeBand{{MinSwing: 75, Multiplier: 1.5}, {MinSwing: 50, Multiplier: 1.25}},
+			CorrelationSymbol:   "BTCUSDT",
+			MinCorrelation:      0.3,
+			DecoupledMultiplier: 0.5,
+		},
+	}}
+	shared := &SharedData{CorrelationKlines: []exchange.Kline{}}
+	newObject := func(name string, percent4Hour float64, correlation float64) SymbolDataObject {
+		return SymbolDataObject{
+			Symbol: exchange.Symbol{Name: name},
+			Values: SymbolDataValues{
+				Percent1Hour: []float64{1},
+				Percent4Hour: percent4Hour,
+				Correlation:  correlation,
+			},
+			data:  ExchangeData{Shared: shared},
+			rules: createRules([]string{"4hr"}, &settings),
+		}
+	}
+
+	// All coins move up together, the 4hr maximum becomes 7.5%.
+	objects := []SymbolDataObject{
+		newObject("AUSDT", 6, 0.8),
+		newObject("BUSDT", 6, 0.1),
+		newObject("CUSDT", 2, 0.8),
+		newObject("DUSDT", 2, 0.8),
+	}
+	for i := range objects {
+		objects[i].checkRules()
+	}
+	if !objects[0].ShouldQuarantine() {
+		t.Fatalf("expected quarantine before applying the regime")
+	}
+
+	a.applyRegime(objects)
+	if objects[0].Multipliers.Percent4Hour != 1.5 {
+		t.Errorf("invalid multiplier: expected %v got %v", 1.5, objects[0].Multipliers.Percent4Hour)
+	}
+	if objects[0].ShouldQuarantine() {
+		t.Errorf("AUSDT: expected no quarantine in a strong market")
+	}
+	if objects[1].Multipliers.Percent4Hour != 0.75 {
+		t.Errorf("invalid decoupled multiplier: expected %v got %v", 0.75, objects[1].Multipliers.Percent4Hour)
+	}
+	if !objects[1].ShouldQuarantine() {
+		t.Errorf("BUSDT: expected quarantine for a decoupled coin")
+	}
+}
+
+func TestApplyRegimeWithoutData(t *testing.T) {
+	a := AutoCoins{Settings: Settings{
+		Regime: SettingsRegime{
+			Enabled: true,
+			Bands:   []SettingsRegimeBand{{MinSwing: 75, Multiplier: 1.5}},
+		},
+	}}
+	objects := []SymbolDataObject{
+		{Symbol: exchange.Symbol{Name: "AUSDT"}, APIFailed: true},
+		{Symbol: exchange.Symbol{Name: "BUSDT"}, APIFailed: true},
+	}
+
+	a.applyRegime(objects)
+	for _, object := range objects {
+		if object.Multipliers != (ThresholdMultipliers{}) {
+			t.Errorf("%s: expected no multipliers without data got %+v", object.Symbol.Name, object.Multipliers)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package autocoins
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Rule checks the calculated market data of a symbol, when a rule does not pass the symbol is quarantined.
+type Rule interface {
+	Name() string
+	Check(*SymbolDataObject) RuleResult
+}
+
+// ThresholdRule is a rule with a single threshold, it can be created again using another threshold.
+type ThresholdRule interface {
+	Rule
+	WithThreshold(threshold float64) Rule
+}
+
+// RuleResult the outcome of a rule for a symbol.
+type RuleResult struct {
+	Rule      string  `json:"rule"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"` // Threshold the effective threshold after the overrides and the market regime.
+	Passed    bool    `json:"passed"`
+	Reason    string  `json:"reason"`
+}
+
+// DefaultRules the rules used when no rules are set in the config file.
+var DefaultRules = []string{"1hr", "4hr", "24hr", "ath", "age"}
+
+// ruleConstructors creates the rule with the name used in the config file.
+var ruleConstructors = map[string]func(*SettingsAutoCoins) Rule{
+	"1hr": func(s *SettingsAutoCoins) Rule {
+		return &Percent1HourRule{MaxPercent: float64(s.Max1hrPercent)}
+	},
+	"4hr": func(s *SettingsAutoCoins) Rule {
+		return &Percent4HourRule{MaxPercent: float64(s.Max4hrPercent)}
+	},
+	"24hr": func(s *SettingsAutoCoins) Rule {
+		return &Percent24HourRule{MaxPercent: float64(s.Max24hrPercent)}
+	},
+	"ath": func(s *SettingsAutoCoins) Rule {
+		return &AllTimeHighRule{MinPercent: float64(s.MinAthPercent)}
+	},
+	"age": func(s *SettingsAutoCoins) Rule {
+		return &AgeRule{MinAge: s.MinAge}
+	},
+	"quoteVolume": func(s *SettingsAutoCoins) Rule {
+		return &QuoteVolumeRule{MinQuoteVolume: s.MinQuoteVolume}
+	},
+	"tradeCount": func(s *SettingsAutoCoins) Rule {
+		return &TradeCountRule{MinTradeCount: s.MinTradeCount}
+	},
+	"volumeSpike": func(s *SettingsAutoCoins) Rule {
+		return &VolumeSpikeRule{MaxVolumeSpike: s.MaxVolumeSpike}
+	},
+	"funding": func(s *SettingsAutoCoins) Rule {
+		return &FundingRateRule{MaxPercent: s.MaxFundingRate}
+	},
+	"openInterest": func(s *SettingsAutoCoins) Rule {
+		return &OpenInterestRule{MaxPercent: s.MaxOpenInterest, Hours: s.OpenInterestHours}
+	},
+	"volatility": func(s *SettingsAutoCoins) Rule {
+		return &VolatilityRule{MaxPercent: s.MaxVolatility}
+	},
+}
+
+// IsRule returns true when a rule with the name exists.
+func IsRule(name string) bool {
+	_, ok := ruleConstructors[name]
+	return ok
+}
+
+// createRules creates the rules in the given order using the thresholds from the settings.
+func createRules(names []string, settings *SettingsAutoCoins) []Rule {
+	rules := []Rule{}
+	for _, name := range names {
+		if constructor, ok := ruleConstructors[name]; ok {
+			rules = append(rules, constructor(settings))
+		}
+	}
+	return rules
+}
+
+// withThresholds returns the rules using the given thresholds by rule name, other rules are unchanged.
+func withThresholds(rules []Rule, thresholds map[string]float64) []Rule {
+	result := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if threshold, ok := thresholds[rule.Name()]; ok {
+			if r, ok := rule.(ThresholdRule); ok {
+				rule = r.WithThreshold(threshold)
+			}
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+// checkMax passes when the absolute value is below the maximum. The format is used for both values.
+func checkMax(rule Rule, label string, value float64, max float64, format string) RuleResult {
+	passed := math.Abs(value) < max
+	reason := fmt.Sprintf("%s "+format+" is below "+format, label, value, max)
+	if !passed {
+		reason = fmt.Sprintf("%s "+format+" exceeds "+format, label, value, max)
+	}
+	return RuleResult{Rule: rule.Name(), Value: value, Threshold: max, Passed: passed, Reason: reason}
+}
+
+// checkMin passes when the value is above the minimum. The format is used for both values.
+func checkMin(rule Rule, label string, value float64, min float64, format string) RuleResult {
+	passed := value > min
+	reason := fmt.Sprintf("%s "+format+" is above "+format, label, value, min)
+	if !passed {
+		reason = fmt.Sprintf("%s "+format+" is not above "+format, label, value, min)
+	}
+	return RuleResult{Rule: rule.Name(), Value: value, Threshold: min, Passed: passed, Reason: reason}
+}
+
+// RuleNames returns the names of the rules joined by a comma.
+func RuleNames(results []RuleResult) string {
+	names := make([]string, 0, len(results))
+	for _, r := range results {
+		names = append(names, r.Rule)
+	}
+	return strings.Join(names, ", ")
+}
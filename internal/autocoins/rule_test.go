@@ -0,0 +1,119 @@
+package autocoins
+
+import "testing"
+
+func TestRules(t *testing.T) {
+	settings := SettingsAutoCoins{
+		Max1hrPercent:  5,
+		Max4hrPercent:  5,
+		Max24hrPercent: 10,
+		CooldownHours:  4,
+		MinAthPercent:  5,
+		MinAge:         14,
+	}
+	safe := SymbolDataValues{
+		Percent1Hour:  []float64{1, -2, 3},
+		Percent4Hour:  -4,
+		Percent24Hour: 9,
+		AllTimeHigh:   20,
+		Age:           100,
+	}
+
+	tests := []struct {
+		name   string
+		update func(v *SymbolDataValues)
+		failed string
+	}{
+		{"safe", func(v *SymbolDataValues) {}, ""},
+		{"1hr", func(v *SymbolDataValues) { v.Percent1Hour = []float64{1, -6, 3} }, "1hr"},
+		{"4hr", func(v *SymbolDataValues) { v.Percent4Hour = 5 }, "4hr"},
+		{"24hr", func(v *SymbolDataValues) { v.Percent24Hour = -12 }, "24hr"},
+		{"ath", func(v *SymbolDataValues) { v.AllTimeHigh = 2 }, "ath"},
+		{"age", func(v *SymbolDataValues) { v.Age = 14 }, "age"},
+	}
+
+	for _, test := range tests {
+		values := safe
+		test.update(&values)
+		object := SymbolDataObject{
+			Values: values,
+			rules:  createRules(DefaultRules, &settings),
+		}
+		object.checkRules()
+
+		if len(object.Results) != len(DefaultRules) {
+			t.Errorf("%s: expected %d results got %d", test.name, len(DefaultRules), len(object.Results))
+		}
+		failed := RuleNames(object.FailedRules())
+		if failed != test.failed {
+			t.Errorf("%s: expected failed rules '%s' got '%s'", test.name, test.failed, failed)
+		}
+		if object.ShouldQuarantine() != (test.failed != "") {
+			t.Errorf("%s: invalid quarantine result", test.name)
+		}
+	}
+}
+
+func TestVolumeRules(t *testing.T) {
+	settings := SettingsAutoCoins{
+		MinQuoteVolume: 1000000,
+		MinTradeCount:  10000,
+		MaxVolumeSpike: 5,
+	}
+	names := []string{"quoteVolume", "tradeCount", "volumeSpike"}
+
+	tests := []struct {
+		name   string
+		values SymbolDataValues
+		failed string
+	}{
+		{"liquid", SymbolDataValues{QuoteVolume: 2000000, TradeCount: 20000, VolumeSpike: 1.2}, ""},
+		{"thin", SymbolDataValues{QuoteVolume: 500000, TradeCount: 5000, VolumeSpike: 1.2}, "quoteVolume, tradeCount"},
+		{"spike", SymbolDataValues{QuoteVolume: 2000000, TradeCount: 20000, VolumeSpike: 8}, "volumeSpike"},
+	}
+
+	for _, test := range tests {
+		object := SymbolDataObject{
+			Values: test.values,
+			rules:  createRules(names, &settings),
+		}
+		object.checkRules()
+
+		failed := RuleNames(object.FailedRules())
+		if failed != test.failed {
+			t.Errorf("%s: expected failed rules '%s' got '%s'", test.name, test.failed, failed)
+		}
+	}
+}
+
+func TestDerivativesRules(t *testing.T) {
+	settings := SettingsAutoCoins{
+		MaxFundingRate:    0.1,
+		MaxOpenInterest:   20,
+		OpenInterestHours: 4,
+	}
+	names := []string{"funding", "openInterest"}
+
+	tests := []struct {
+		name   string
+		values SymbolDataValues
+		failed string
+	}{
+		{"normal", SymbolDataValues{FundingRate: 0.01, OpenInterest: 5}, ""},
+		{"negative funding", SymbolDataValues{FundingRate: -0.3, OpenInterest: 5}, "funding"},
+		{"open interest drop", SymbolDataValues{FundingRate: 0.01, OpenInterest: -25}, "openInterest"},
+	}
+
+	for _, test := range tests {
+		object := SymbolDataObject{
+			Values: test.values,
+			rules:  createRules(names, &settings),
+		}
+		object.checkRules()
+
+		failed := RuleNames(object.FailedRules())
+		if failed != test.failed {
+			t.Errorf("%s: expected failed rules '%s' got '%s'", test.name, test.failed, failed)
+		}
+	}
+}
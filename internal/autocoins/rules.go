@@ -0,0 +1,228 @@
+package autocoins
+
+import (
+	"fmt"
+	"math"
+)
+
+// Percent1HourRule quarantines when one of the 1 hour price changes within the cooldown exceeds the maximum.
+type Percent1HourRule struct {
+	MaxPercent float64
+}
+
+func (r *Percent1HourRule) Name() string {
+	return "1hr"
+}
+
+func (r *Percent1HourRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MaxPercent = threshold
+	return &rule
+}
+
+func (r *Percent1HourRule) Check(s *SymbolDataObject) RuleResult {
+	max := 0.0
+	for _, val := range s.Values.Percent1Hour {
+		if math.Abs(val) > math.Abs(max) {
+			max = val
+		}
+	}
+	return checkMax(r, "1hr change", max, scaleMax(r.MaxPercent, s.Multipliers.Percent1Hour), "%.2f%%")
+}
+
+// Percent4HourRule quarantines when the 4 hour price change exceeds the maximum.
+type Percent4HourRule struct {
+	MaxPercent float64
+}
+
+func (r *Percent4HourRule) Name() string {
+	return "4hr"
+}
+
+func (r *Percent4HourRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MaxPercent = threshold
+	return &rule
+}
+
+func (r *Percent4HourRule) Check(s *SymbolDataObject) RuleResult {
+	return checkMax(r, "4hr change", s.Values.Percent4Hour, scaleMax(r.MaxPercent, s.Multipliers.Percent4Hour), "%.2f%%")
+}
+
+// Percent24HourRule quarantines when the 24 hour price change exceeds the maximum.
+type Percent24HourRule struct {
+	MaxPercent float64
+}
+
+func (r *Percent24HourRule) Name() string {
+	return "24hr"
+}
+
+func (r *Percent24HourRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MaxPercent = threshold
+	return &rule
+}
+
+func (r *Percent24HourRule) Check(s *SymbolDataObject) RuleResult {
+	return checkMax(r, "24hr change", s.Values.Percent24Hour, scaleMax(r.MaxPercent, s.Multipliers.Percent24Hour), "%.2f%%")
+}
+
+// AllTimeHighRule quarantines when the price is too close to the all time high.
+type AllTimeHighRule struct {
+	MinPercent float64
+}
+
+func (r *AllTimeHighRule) Name() string {
+	return "ath"
+}
+
+func (r *AllTimeHighRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MinPercent = threshold
+	return &rule
+}
+
+func (r *AllTimeHighRule) Check(s *SymbolDataObject) RuleResult {
+	return checkMin(r, "distance to ATH", s.Values.AllTimeHigh, r.MinPercent, "%.2f%%")
+}
+
+// AgeRule quarantines symbols that are listed too recently.
+type AgeRule struct {
+	MinAge int
+}
+
+func (r *AgeRule) Name() string {
+	return "age"
+}
+
+func (r *AgeRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MinAge = int(threshold)
+	return &rule
+}
+
+func (r *AgeRule) Check(s *SymbolDataObject) RuleResult {
+	return checkMin(r, "age", float64(s.Values.Age), float64(r.MinAge), "%.0f days")
+}
+
+// QuoteVolumeRule quarantines illiquid symbols with a low 24 hour quote volume.
+type QuoteVolumeRule struct {
+	MinQuoteVolume float64
+}
+
+func (r *QuoteVolumeRule) Name() string {
+	return "quoteVolume"
+}
+
+func (r *QuoteVolumeRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MinQuoteVolume = threshold
+	return &rule
+}
+
+func (r *QuoteVolumeRule) Check(s *SymbolDataObject) RuleResult {
+	return checkMin(r, "24hr quote volume", s.Values.QuoteVolume, r.MinQuoteVolume, "%.0f")
+}
+
+// TradeCountRule quarantines illiquid symbols with a low number of trades in 24 hours.
+type TradeCountRule struct {
+	MinTradeCount int64
+}
+
+func (r *TradeCountRule) Name() string {
+	return "tradeCount"
+}
+
+func (r *TradeCountRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MinTradeCount = int64(threshold)
+	return &rule
+}
+
+func (r *TradeCountRule) Check(s *SymbolDataObject) RuleResult {
+	return checkMin(r, "24hr trades", float64(s.Values.TradeCount), float64(r.MinTradeCount), "%.0f")
+}
+
+// VolumeSpikeRule quarantines when the recent volume is a multiple of the average volume.
+type VolumeSpikeRule struct {
+	MaxVolumeSpike float64
+}
+
+func (r *VolumeSpikeRule) Name() string {
+	return "volumeSpike"
+}
+
+func (r *VolumeSpikeRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MaxVolumeSpike = threshold
+	return &rule
+}
+
+func (r *VolumeSpikeRule) Check(s *SymbolDataObject) RuleResult {
+	return checkMax(r, "volume spike", s.Values.VolumeSpike, r.MaxVolumeSpike, "%.2fx")
+}
+
+// FundingRateRule quarantines when the absolute funding rate is extreme.
+type FundingRateRule struct {
+	MaxPercent float64
+}
+
+func (r *FundingRateRule) Name() string {
+	return "funding"
+}
+
+func (r *FundingRateRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MaxPercent = threshold
+	return &rule
+}
+
+func (r *FundingRateRule) Check(s *SymbolDataObject) RuleResult {
+	return checkMax(r, "funding rate", s.Values.FundingRate, r.MaxPercent, "%.4f%%")
+}
+
+// OpenInterestRule quarantines when the open interest changed too much in the last hours.
+type OpenInterestRule struct {
+	MaxPercent float64
+	Hours      int
+}
+
+func (r *OpenInterestRule) Name() string {
+	return "openInterest"
+}
+
+func (r *OpenInterestRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MaxPercent = threshold
+	return &rule
+}
+
+func (r *OpenInterestRule) Check(s *SymbolDataObject) RuleResult {
+	return checkMax(r, fmt.Sprintf("%dhr open interest change", r.Hours), s.Values.OpenInterest, r.MaxPercent, "%.2f%%")
+}
+
+// VolatilityRule quarantines when the hourly high to low range within the volatility cooldown exceeds the maximum.
+type VolatilityRule struct {
+	MaxPercent float64
+}
+
+func (r *VolatilityRule) Name() string {
+	return "volatility"
+}
+
+func (r *VolatilityRule) WithThreshold(threshold float64) Rule {
+	rule := *r
+	rule.MaxPercent = threshold
+	return &rule
+}
+
+func (r *VolatilityRule) Check(s *SymbolDataObject) RuleResult {
+	max := 0.0
+	for _, val := range s.Values.Volatility {
+		if val > max {
+			max = val
+		}
+	}
+	return checkMax(r, "1hr range", max, r.MaxPercent, "%.2f%%")
+}
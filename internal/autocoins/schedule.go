@@ -0,0 +1,264 @@
+package autocoins
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// ScheduleTimeLayout the layout of the start and end time of a schedule.
+const ScheduleTimeLayout = "15:04"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ApplySchedule applies the first active schedule to the settings and returns its name.
+// Returns an empty string when no schedule is active, the settings are not changed.
+func (a *AutoCoins) ApplySchedule(now time.Time) string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.schedule = ""
+	schedule := a.Settings.ActiveSchedule(now)
+	if schedule == nil {
+		return ""
+	}
+	if len(schedule.AutoCoins) > 0 {
+		// Only the fields set in the schedule replace the thresholds.
+		if err := json.Unmarshal(schedule.AutoCoins, &a.Settings.AutoCoins); err != nil {
+			logger.Errorf("schedule %s: %s\n", schedule.Name, err.Error())
+		}
+	}
+	filters := &a.Settings.Filters
+	for _, symbol := range schedule.BlackList {
+		if !ContainsString(filters.BlackList, symbol) {
+			filters.BlackList = append(append([]string{}, filters.BlackList...), symbol)
+		}
+	}
+	sort.Strings(filters.BlackList)
+
+	a.schedule = schedule.Name
+	return schedule.Name
+}
+
+// currentSchedule returns the name of the schedule applied to the current run.
+func (a *AutoCoins) currentSchedule() string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.schedule
+}
+
+// ActiveSchedule returns the first schedule that is active at the given time, nil when none is active.
+func (s *Settings) ActiveSchedule(now time.Time) *SettingsSchedule {
+	for i := range s.Schedules {
+		active, err := s.Schedules[i].IsActive(now)
+		if err != nil {
+			logger.Errorf("schedule %s: %s\n", s.Schedules[i].Name, err.Error())
+			continue
+		}
+		if active {
+			return &s.Schedules[i]
+		}
+	}
+	return nil
+}
+
+// IsActive returns true when the time is within the window of the schedule.
+func (s *SettingsSchedule) IsActive(now time.Time) (bool, error) {
+	location := time.UTC
+	if s.Timezone != "" {
+		l, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			return false, err
+		}
+		location = l
+	}
+	t := now.In(location)
+
+	if s.Cron != "" {
+		cron, err := parseCron(s.Cron)
+		if err != nil {
+			return false, err
+		}
+		minutes := s.Minutes
+		if minutes < 1 {
+			minutes = 1
+		}
+		start := t.Truncate(time.Minute)
+		for m := 0; m < minutes; m++ {
+			if cron.matches(start.Add(-time.Duration(m) * time.Minute)) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	start, end := 0, 24*60
+	var err error
+	if s.Start != "" {
+		if start, err = parseClock(s.Start); err != nil {
+			return false, err
+		}
+	}
+	if s.End != "" {
+		if end, err = parseClock(s.End); err != nil {
+			return false, err
+		}
+	}
+	clock := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	switch {
+	case start < end:
+		if clock < start || clock >= end {
+			return false, nil
+		}
+	case start > end:
+		// The window ends on the next day.
+		if clock < end {
+			day = (day + 6) % 7
+		} else if clock < start {
+			return false, nil
+		}
+	}
+	return s.onDay(day)
+}
+
+func (s *SettingsSchedule) onDay(day time.Weekday) (bool, error) {
+	if len(s.Days) == 0 {
+		return true, nil
+	}
+	for _, d := range s.Days {
+		weekday, ok := weekdays[strings.ToLower(d)]
+		if !ok {
+			return false, fmt.Errorf("unknown day '%s'", d)
+		}
+		if weekday == day {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *SettingsSchedule) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if s.Cron != "" && (s.Start != "" || s.End != "" || len(s.Days) > 0) {
+		return fmt.Errorf("use either cron or days, start and end")
+	}
+	if len(s.AutoCoins) > 0 {
+		var settings SettingsAutoCoins
+		if err := json.Unmarshal(s.AutoCoins, &settings); err != nil {
+			return fmt.Errorf("autoCoins: %s", err.Error())
+		}
+	}
+	_, err := s.IsActive(time.Now())
+	return err
+}
+
+// parseClock parses "15:04" and returns the minutes since midnight.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse(ScheduleTimeLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s' (use %s)", value, ScheduleTimeLayout)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// cronSchedule the allowed values of the minute, hour, day of month, month and day of week fields.
+type cronSchedule struct {
+	fields     [5]map[int]bool
+	anyDay     bool // anyDay the day of month field is `*`.
+	anyWeekday bool // anyWeekday the day of week field is `*`.
+}
+
+var cronRanges = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// parseCron parses a cron expression with 5 fields: minute hour day-of-month month day-of-week.
+// A field is `*`, a value, a range `1-5`, a list `1,15` or any of these with a step `*/15`.
+func parseCron(expression string) (*cronSchedule, error) {
+	parts := strings.Fields(expression)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("invalid cron '%s' (use minute hour day month weekday)", expression)
+	}
+	cron := &cronSchedule{
+		anyDay:     parts[2] == "*",
+		anyWeekday: parts[4] == "*",
+	}
+	for i, part := range parts {
+		values, err := parseCronField(part, cronRanges[i][0], cronRanges[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron '%s': %s", expression, err.Error())
+		}
+		cron.fields[i] = values
+	}
+	if cron.fields[4][7] {
+		cron.fields[4][0] = true
+	}
+	return cron, nil
+}
+
+func parseCronField(field string, min int, max int) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, item := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			s, err := strconv.Atoi(item[i+1:])
+			if err != nil || s < 1 {
+				return nil, fmt.Errorf("invalid step '%s'", item)
+			}
+			step = s
+			item = item[:i]
+		}
+
+		from, to := min, max
+		if item != "*" {
+			bounds := strings.SplitN(item, "-", 2)
+			value, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value '%s'", item)
+			}
+			from, to = value, value
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid value '%s'", item)
+				}
+			} else if step > 1 {
+				to = max
+			}
+		}
+		if from < min || to > max || from > to {
+			return nil, fmt.Errorf("value '%s' out of range %d-%d", item, min, max)
+		}
+		for v := from; v <= to; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// matches returns true when the minute of the time matches the cron expression.
+// Like cron, when both the day of month and day of week are set either one has to match.
+func (c *cronSchedule) matches(t time.Time) bool {
+	if !c.fields[0][t.Minute()] || !c.fields[1][t.Hour()] || !c.fields[3][int(t.Month())] {
+		return false
+	}
+	day := c.fields[2][t.Day()]
+	weekday := c.fields[4][int(t.Weekday())]
+	if !c.anyDay && !c.anyWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
@@ -0,0 +1,84 @@
+package autocoins
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestScheduleIsActive(t *testing.T) {
+	// 2021-06-04 is a Friday.
+	friday := func(hour int, minute int) time.Time {
+		return time.Date(2021, 6, 4, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		schedule SettingsSchedule
+		time     time.Time
+		active   bool
+	}{
+		{"every day", SettingsSchedule{}, friday(12, 0), true},
+		{"within window", SettingsSchedule{Start: "13:00", End: "15:00"}, friday(13, 30), true},
+		{"end is exclusive", SettingsSchedule{Start: "13:00", End: "15:00"}, friday(15, 0), false},
+		{"other day", SettingsSchedule{Days: []string{"mon", "tue"}}, friday(12, 0), false},
+		{"overnight before midnight", SettingsSchedule{Days: []string{"fri"}, Start: "22:00", End: "06:00"}, friday(23, 0), true},
+		{"overnight after midnight", SettingsSchedule{Days: []string{"thu"}, Start: "22:00", End: "06:00"}, friday(5, 0), true},
+		{"overnight next day", SettingsSchedule{Days: []string{"fri"}, Start: "22:00", End: "06:00"}, friday(5, 0), false},
+		{"timezone", SettingsSchedule{Start: "09:30", End: "10:30", Timezone: "America/New_York"}, friday(13, 45), true},
+		{"cron within minutes", SettingsSchedule{Cron: "0 0,8,16 * * *", Minutes: 30}, friday(16, 29), true},
+		{"cron after minutes", SettingsSchedule{Cron: "0 0,8,16 * * *", Minutes: 30}, friday(16, 30), false},
+		{"cron weekday", SettingsSchedule{Cron: "0 22 * * 0", Minutes: 120}, friday(22, 30), false},
+		{"cron step", SettingsSchedule{Cron: "*/15 * * * 5", Minutes: 1}, friday(10, 45), true},
+	}
+	for _, test := range tests {
+		active, err := test.schedule.IsActive(test.time)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err.Error())
+			continue
+		}
+		if active != test.active {
+			t.Errorf("%s: expected active %v got %v", test.name, test.active, active)
+		}
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	invalid := []SettingsSchedule{
+		{Start: "13:00"},
+		{Name: "a", Start: "25:00"},
+		{Name: "a", Days: []string{"friday"}},
+		{Name: "a", Cron: "0 0 * *"},
+		{Name: "a", Cron: "60 0 * * *"},
+		{Name: "a", Cron: "0 0 * * *", Start: "13:00"},
+		{Name: "a", AutoCoins: json.RawMessage(`{"max1hrPercent": "5"}`)},
+	}
+	for _, schedule := range invalid {
+		if err := schedule.validate(); err == nil {
+			t.Errorf("%+v: expected an error", schedule)
+		}
+	}
+}
+
+func TestApplySchedule(t *testing.T) {
+	a := AutoCoins{Settings: Settings{
+		AutoCoins: SettingsAutoCoins{Max1hrPercent: 5, Max4hrPercent: 5},
+		Filters:   SettingsFilters{BlackList: []string{"BTCUSDT"}},
+		Schedules: []SettingsSchedule{
+			{Name: "weekend", Days: []string{"sat", "sun"}, AutoCoins: json.RawMessage(`{"max1hrPercent": 10}`)},
+			{Name: "open", Start: "13:30", End: "15:00", AutoCoins: json.RawMessage(`{"max1hrPercent": 3}`), BlackList: []string{"XYZUSDT"}},
+		},
+	}}
+
+	if name := a.ApplySchedule(time.Date(2021, 6, 4, 12, 0, 0, 0, time.UTC)); name != "" || a.Settings.AutoCoins.Max1hrPercent != 5 {
+		t.Errorf("expected no schedule got '%s' with max1hrPercent %d", name, a.Settings.AutoCoins.Max1hrPercent)
+	}
+	if name := a.ApplySchedule(time.Date(2021, 6, 4, 14, 0, 0, 0, time.UTC)); name != "open" {
+		t.Errorf("invalid schedule: expected %s got %s", "open", name)
+	}
+	if a.Settings.AutoCoins.Max1hrPercent != 3 || a.Settings.AutoCoins.Max4hrPercent != 5 {
+		t.Errorf("invalid thresholds: expected 3 and 5 got %d and %d", a.Settings.AutoCoins.Max1hrPercent, a.Settings.AutoCoins.Max4hrPercent)
+	}
+	if !ContainsString(a.Settings.Filters.BlackList, "XYZUSDT") || !ContainsString(a.Settings.Filters.BlackList, "BTCUSDT") {
+		t.Errorf("invalid blacklist: got %v", a.Settings.Filters.BlackList)
+	}
+}
@@ -0,0 +1,233 @@
+// Package server is the local HTTP API to view the status of and control a running AutoCoins.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// Server serves the status and control API.
+//
+//	GET    /status     running state, pause state and API weight
+//	GET    /run        lists, market swing and values of the last run
+//	GET    /settings   current settings (secrets removed)
+//	POST   /run        start a run now
+//	POST   /pause      skip the scheduled runs
+//	POST   /resume     resume the scheduled runs
+//	GET    /overrides  temporary exclude and blacklist entries
+//	POST   /overrides  add an entry: {"symbol": "XYZUSDT", "list": "exclude", "minutes": 60}
+//	DELETE /overrides  remove an entry: ?symbol=XYZUSDT&list=exclude
+type Server struct {
+	AutoCoins *autocoins.AutoCoins
+	Token     string // Token (optional) required as bearer token for all requests.
+	server    *http.Server
+}
+
+func New(a *autocoins.AutoCoins, address string, token string) *Server {
+	s := &Server{
+		AutoCoins: a,
+		Token:     token,
+	}
+	s.server = &http.Server{
+		Addr:    address,
+		Handler: s.Handler(),
+	}
+	return s
+}
+
+// Handler returns the handler with all the endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/run", s.handleRun)
+	mux.HandleFunc("/settings", s.handleSettings)
+	mux.HandleFunc("/pause", s.handlePause)
+	mux.HandleFunc("/resume", s.handleResume)
+	mux.HandleFunc("/overrides", s.handleOverrides)
+	return s.authorize(mux)
+}
+
+// Start listens in the background until Stop is called.
+func (s *Server) Start() {
+	logger.Infof("Control API listening on http://%s\n", s.server.Addr)
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("control API: %s\n", err.Error())
+		}
+	}()
+}
+
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.server.Shutdown(ctx)
+}
+
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Token != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+				writeError(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type statusResponse struct {
+	Running bool      `json:"running"`
+	Paused  bool      `json:"paused"`
+	LastRun time.Time `json:"lastRun,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Weight  struct {
+		Used  int `json:"used"`
+		Limit int `json:"limit"`
+	} `json:"weight"`
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	response := statusResponse{
+		Running: s.AutoCoins.IsRunning(),
+		Paused:  s.AutoCoins.IsPaused(),
+	}
+	weight := s.AutoCoins.ExchangeAPI.Weight()
+	response.Weight.Used = weight.Used
+	response.Weight.Limit = weight.Limit
+	if run, ok := s.AutoCoins.LastRun(); ok {
+		response.LastRun = run.Time
+		response.Error = run.Error
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	if r.Method == http.MethodPost {
+		s.AutoCoins.TriggerRun()
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "run triggered"})
+		return
+	}
+	run, ok := s.AutoCoins.LastRun()
+	if !ok {
+		writeError(w, http.StatusNotFound, "no run finished yet")
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+func (s *Server) handleSettings(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	settings := s.AutoCoins.CurrentSettings()
+	settings.Discord.WebHook = redact(settings.Discord.WebHook)
+	settings.Proxy.Password = redact(settings.Proxy.Password)
+	settings.Filters.GoogleSheet.APIKey = redact(settings.Filters.GoogleSheet.APIKey)
+	settings.Telegram.Token = redact(settings.Telegram.Token)
+	settings.DiscordBot.Token = redact(settings.DiscordBot.Token)
+	settings.Outputs.Slack.WebHook = redact(settings.Outputs.Slack.WebHook)
+	// The webhooks are shared with the settings of AutoCoins.
+	webhooks := make([]autocoins.SettingsWebhook, len(settings.Outputs.Webhooks))
+	for i, webhook := range settings.Outputs.Webhooks {
+		webhook.URL = redact(webhook.URL)
+		webhook.Headers = nil
+		webhooks[i] = webhook
+	}
+	settings.Outputs.Webhooks = webhooks
+	writeJSON(w, http.StatusOK, settings)
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	s.AutoCoins.Pause()
+	writeJSON(w, http.StatusOK, map[string]string{"status": "paused"})
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	s.AutoCoins.Resume()
+	writeJSON(w, http.StatusOK, map[string]string{"status": "resumed"})
+}
+
+type overrideRequest struct {
+	Symbol  string `json:"symbol"`
+	List    string `json:"list"`
+	Minutes int    `json:"minutes"` // Minutes 0 keeps the override until the program exits.
+}
+
+func (s *Server) handleOverrides(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost, http.MethodDelete) {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.AutoCoins.Overrides())
+	case http.MethodPost:
+		var request overrideRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+			return
+		}
+		override, err := s.AutoCoins.AddOverride(strings.ToUpper(request.Symbol), request.List, time.Duration(request.Minutes)*time.Minute)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, override)
+	case http.MethodDelete:
+		symbol := strings.ToUpper(r.URL.Query().Get("symbol"))
+		if !s.AutoCoins.RemoveOverride(symbol, r.URL.Query().Get("list")) {
+			writeError(w, http.StatusNotFound, "override not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logger.Errorf("control API write: %s\n", err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "***"
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+type stubExchange struct{}
+
+func (e *stubExchange) GetSymbols() ([]exchange.Symbol, error) { return nil, nil }
+func (e *stubExchange) GetTickers() ([]exchange.Ticker, error) { return nil, nil }
+func (e *stubExchange) GetKline(symbol exchange.Symbol, interval exchange.KlineInterval, limit int) ([]exchange.Kline, error) {
+	return nil, nil
+}
+func (e *stubExchange) RateLimitChecks(symbolCount int) {}
+func (e *stubExchange) Weight() exchange.Weight         { return exchange.Weight{Used: 10, Limit: 2400} }
+func (e *stubExchange) Cancel()                         {}
+
+func newTestServer(token string) *Server {
+	a := &autocoins.AutoCoins{ExchangeAPI: &stubExchange{}}
+	a.Settings.Proxy.Password = "secret"
+	a.Settings.Telegram.Token = "secret"
+	a.Settings.Outputs.Webhooks = []autocoins.SettingsWebhook{{URL: "https://example.com/secret", Headers: map[string]string{"Authorization": "secret"}}}
+	return New(a, "127.0.0.1:0", token)
+}
+
+func request(s *Server, method string, target string, body string, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, r)
+	return w
+}
+
+func TestStatusAndControl(t *testing.T) {
+	s := newTestServer("")
+
+	if w := request(s, http.MethodPost, "/pause", "", ""); w.Code != http.StatusOK {
+		t.Fatalf("pause: expected %d got %d", http.StatusOK, w.Code)
+	}
+	w := request(s, http.MethodGet, "/status", "", "")
+	var status statusResponse
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("invalid status response: %s", err.Error())
+	}
+	if !status.Paused || status.Weight.Used != 10 {
+		t.Errorf("invalid status: expected paused with weight 10 got %+v", status)
+	}
+
+	if w := request(s, http.MethodGet, "/run", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("run: expected %d without a run got %d", http.StatusNotFound, w.Code)
+	}
+	if w := request(s, http.MethodPut, "/run", "", ""); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("run: expected %d got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+
+	w = request(s, http.MethodGet, "/settings", "", "")
+	if strings.Contains(w.Body.String(), "secret") {
+		t.Errorf("settings: secrets not removed")
+	}
+	if s.AutoCoins.Settings.Outputs.Webhooks[0].URL != "https://example.com/secret" {
+		t.Errorf("settings: the webhooks of AutoCoins changed")
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	s := newTestServer("")
+
+	if w := request(s, http.MethodPost, "/overrides", `{"symbol": "xyzusdt", "list": "blacklist", "minutes": 60}`, ""); w.Code != http.StatusCreated {
+		t.Fatalf("add override: expected %d got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if w := request(s, http.MethodPost, "/overrides", `{"symbol": "XYZUSDT", "list": "other"}`, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("add invalid override: expected %d got %d", http.StatusBadRequest, w.Code)
+	}
+
+	overrides := s.AutoCoins.Overrides()
+	if len(overrides) != 1 || overrides[0].Symbol != "XYZUSDT" || overrides[0].Until.IsZero() {
+		t.Errorf("invalid overrides: %+v", overrides)
+	}
+
+	if w := request(s, http.MethodDelete, "/overrides?symbol=XYZUSDT&list=blacklist", "", ""); w.Code != http.StatusNoContent {
+		t.Errorf("remove override: expected %d got %d", http.StatusNoContent, w.Code)
+	}
+	if len(s.AutoCoins.Overrides()) != 0 {
+		t.Errorf("override not removed")
+	}
+}
+
+func TestToken(t *testing.T) {
+	s := newTestServer("token")
+
+	if w := request(s, http.MethodGet, "/status", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d without token got %d", http.StatusUnauthorized, w.Code)
+	}
+	if w := request(s, http.MethodGet, "/status", "", "token"); w.Code != http.StatusOK {
+		t.Errorf("expected %d with token got %d", http.StatusOK, w.Code)
+	}
+}
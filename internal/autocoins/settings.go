@@ -2,18 +2,131 @@ package autocoins
 
 import (
 	"encoding/json"
-	"log"
+	"fmt"
 	"os"
 	"sort"
+	"strings"
+
+	"github.com/LompeBoer/go-autocoins/internal/logger"
 )
 
 type SettingsAutoCoins struct {
-	Max1hrPercent  int `json:"max1hrPercent"`
-	Max4hrPercent  int `json:"max4hrPercent"`
-	Max24hrPercent int `json:"max24hrPercent"`
-	CooldownHours  int `json:"cooldownHrs"`
-	MinAthPercent  int `json:"minAthPercent"`
-	MinAge         int `json:"minAge"`
+	Max1hrPercent           int     `json:"max1hrPercent"`
+	Max4hrPercent           int     `json:"max4hrPercent"`
+	Max24hrPercent          int     `json:"max24hrPercent"`
+	CooldownHours           int     `json:"cooldownHrs"`
+	MinAthPercent           int     `json:"minAthPercent"`
+	MinAge                  int     `json:"minAge"`
+	MinQuoteVolume          float64 `json:"minQuoteVolume"`
+	MinTradeCount           int64   `json:"minTradeCount"`
+	MaxVolumeSpike          float64 `json:"maxVolumeSpike"`
+	VolumeSpikeMinutes      int     `json:"volumeSpikeMins"`
+	MaxFundingRate          float64 `json:"maxFundingRatePercent"`
+	MaxOpenInterest         float64 `json:"maxOpenInterestPercent"`
+	OpenInterestHours       int     `json:"openInterestHrs"`
+	MaxVolatility           float64 `json:"maxVolatilityPercent"`
+	VolatilityCooldownHours int     `json:"volatilityCooldownHrs"`
+}
+
+// SettingsThresholdOverrides overrides the rule thresholds of SettingsAutoCoins for groups and symbols.
+// Groups are applied in order, then the glob patterns and last the symbol names, a later override wins.
+type SettingsThresholdOverrides struct {
+	Groups  []SettingsThresholdGroup      `json:"groups"`
+	Symbols map[string]map[string]float64 `json:"symbols"` // Symbols thresholds by rule name keyed by symbol name or glob pattern (e.g. "*DOGE*").
+}
+
+// SettingsThresholdGroup a named group of symbols with its own thresholds.
+// A symbol is a member when it matches one of the symbols or its 24hr quote volume rank is within the rank range.
+type SettingsThresholdGroup struct {
+	Name          string             `json:"name"`
+	Symbols       []string           `json:"symbols"`       // Symbols symbol names or glob patterns.
+	MinVolumeRank int                `json:"minVolumeRank"` // MinVolumeRank first rank by 24hr quote volume, 1 is the highest volume.
+	MaxVolumeRank int                `json:"maxVolumeRank"` // MaxVolumeRank last rank by 24hr quote volume, 0 does not use the rank.
+	Thresholds    map[string]float64 `json:"thresholds"`    // Thresholds threshold by rule name.
+}
+
+// SettingsSchedule a time window with other thresholds and extra blacklisted symbols.
+// The window is either a cron expression with a duration or the days with a start and end time.
+type SettingsSchedule struct {
+	Name      string          `json:"name"`
+	Days      []string        `json:"days"`      // Days "mon" to "sun", empty is every day.
+	Start     string          `json:"start"`     // Start "15:04", empty is midnight.
+	End       string          `json:"end"`       // End "15:04", empty is midnight, before Start the window ends on the next day.
+	Cron      string          `json:"cron"`      // Cron start of the window "minute hour day month weekday".
+	Minutes   int             `json:"minutes"`   // Minutes duration of the cron window.
+	Timezone  string          `json:"timezone"`  // Timezone e.g. "America/New_York", empty is UTC.
+	AutoCoins json.RawMessage `json:"autoCoins"` // AutoCoins the autoCoins settings that are replaced, other settings are unchanged.
+	BlackList []string        `json:"blackList"` // BlackList symbols added to the blacklist.
+}
+
+// SettingsQuarantine prevents symbols from flipping between quarantined and permitted on consecutive runs.
+type SettingsQuarantine struct {
+	MinMinutes        int                `json:"minMinutes"`        // MinMinutes minimum time a symbol stays quarantined.
+	CleanRuns         int                `json:"cleanRuns"`         // CleanRuns consecutive runs passing the release thresholds before the symbol is permitted.
+	ReleaseThresholds map[string]float64 `json:"releaseThresholds"` // ReleaseThresholds threshold by rule name used for quarantined symbols.
+}
+
+// SettingsServer the local HTTP API to view the status of and control the running program.
+type SettingsServer struct {
+	Enabled bool   `json:"enabled"`
+	Address string `json:"address"`
+	Token   string `json:"token"` // Token (optional) required as bearer token.
+}
+
+// SettingsDiscordBot the Discord slash commands, Discord sends them to the interactions endpoint on the address.
+type SettingsDiscordBot struct {
+	Enabled       bool     `json:"enabled"`
+	Address       string   `json:"address"`
+	ApplicationID string   `json:"applicationId"`
+	PublicKey     string   `json:"publicKey"`
+	Token         string   `json:"token"`   // Token of the bot, used to register the commands.
+	GuildID       string   `json:"guildId"` // GuildID (optional) registers the commands in the server instead of globally.
+	Roles         []string `json:"roles"`   // Roles the ids of the roles that can use the control commands.
+}
+
+// SettingsMetrics the Prometheus metrics endpoint (/metrics).
+type SettingsMetrics struct {
+	Enabled bool   `json:"enabled"`
+	Address string `json:"address"`
+}
+
+// SettingsJSONLines writes every run as JSON Lines to a rotating file.
+type SettingsJSONLines struct {
+	Enabled    bool   `json:"enabled"`
+	File       string `json:"file"`       // File relative to the storage file directory.
+	Symbols    bool   `json:"symbols"`    // Symbols also write a record for every symbol.
+	MaxSizeMB  int    `json:"maxSizeMB"`  // MaxSizeMB the file is rotated when it exceeds this size, 0 never rotates.
+	MaxBackups int    `json:"maxBackups"` // MaxBackups number of rotated files to keep.
+}
+
+// SettingsLog the log level and the log file.
+type SettingsLog struct {
+	Level      string `json:"level"`      // Level debug, info, warn or error.
+	File       string `json:"file"`       // File (optional) relative to the storage file directory, empty only logs to the console.
+	MaxSizeMB  int    `json:"maxSizeMB"`  // MaxSizeMB the file is rotated when it exceeds this size, 0 never rotates.
+	Daily      bool   `json:"daily"`      // Daily rotates the file every day instead of by size.
+	MaxBackups int    `json:"maxBackups"` // MaxBackups number of rotated files to keep.
+}
+
+// SettingsHistory records every run in the AutoCoins database.
+type SettingsHistory struct {
+	Enabled       bool `json:"enabled"`
+	RetentionDays int  `json:"retentionDays"` // RetentionDays runs older than this are removed, 0 keeps all runs.
+}
+
+// SettingsRegime scales the 1hr, 4hr and 24hr maximum price changes to the market regime.
+type SettingsRegime struct {
+	Enabled             bool                 `json:"enabled"`
+	Bands               []SettingsRegimeBand `json:"bands"`
+	CorrelationSymbol   string               `json:"correlationSymbol"`
+	MinCorrelation      float64              `json:"minCorrelation"`
+	DecoupledMultiplier float64              `json:"decoupledMultiplier"`
+}
+
+// SettingsRegimeBand the multiplier used when the absolute market swing is at least MinSwing percent.
+type SettingsRegimeBand struct {
+	MinSwing   float64 `json:"minSwing"`
+	Multiplier float64 `json:"multiplier"`
 }
 
 type SettingsFilterGoogleSheet struct {
@@ -34,6 +147,41 @@ type SettingsFilters struct {
 type SettingsDiscord struct {
 	WebHook        string `json:"webHook"`
 	MentionOnError bool   `json:"mentionOnError"`
+	Details        bool   `json:"details"` // Details lists the rules and values that quarantined the new coins.
+}
+
+type SettingsTelegram struct {
+	Token         string `json:"token"`
+	ChatID        string `json:"chatId"`
+	Silent        bool   `json:"silent"`
+	NotifyOnError bool   `json:"notifyOnError"`
+}
+
+// SettingsOutputs the additional outputs of the runs.
+type SettingsOutputs struct {
+	Slack    SettingsSlack     `json:"slack"`
+	Webhooks []SettingsWebhook `json:"webhooks"`
+}
+
+type SettingsSlack struct {
+	WebHook        string `json:"webHook"`
+	MentionOnError bool   `json:"mentionOnError"`
+}
+
+// SettingsWebhook a generic webhook, the body is rendered by a Go text/template from the run.
+type SettingsWebhook struct {
+	Name          string            `json:"name"`
+	URL           string            `json:"url"`
+	Method        string            `json:"method"`        // Method default POST.
+	ContentType   string            `json:"contentType"`   // ContentType default application/json.
+	Headers       map[string]string `json:"headers"`       // Headers for example an Authorization header.
+	Template      string            `json:"template"`      // Template of the body of a run, gets WebhookData.
+	ErrorTemplate string            `json:"errorTemplate"` // ErrorTemplate (optional) of the body of an error.
+}
+
+type SettingsMarketData struct {
+	Stream bool `json:"stream"`
+	Store  bool `json:"store"`
 }
 
 type SettingsProxy struct {
@@ -43,15 +191,29 @@ type SettingsProxy struct {
 }
 
 type Settings struct {
-	configFilename string
-	Version        int               `json:"version"`
-	API            string            `json:"api"`
-	Exchange       string            `json:"exchange"`
-	Refresh        int               `json:"refresh"`
-	AutoCoins      SettingsAutoCoins `json:"autoCoins"`
-	Filters        SettingsFilters   `json:"filters"`
-	Discord        SettingsDiscord   `json:"discord"`
-	Proxy          SettingsProxy     `json:"proxy"`
+	configFilename     string
+	Version            int                        `json:"version"`
+	API                string                     `json:"api"`
+	Exchange           string                     `json:"exchange"`
+	Refresh            int                        `json:"refresh"`
+	AutoCoins          SettingsAutoCoins          `json:"autoCoins"`
+	Rules              []string                   `json:"rules"`
+	ThresholdOverrides SettingsThresholdOverrides `json:"thresholdOverrides"`
+	Schedules          []SettingsSchedule         `json:"schedules"`
+	Filters            SettingsFilters            `json:"filters"`
+	Discord            SettingsDiscord            `json:"discord"`
+	Telegram           SettingsTelegram           `json:"telegram"`
+	Outputs            SettingsOutputs            `json:"outputs"`
+	MarketData         SettingsMarketData         `json:"marketData"`
+	Regime             SettingsRegime             `json:"regime"`
+	Quarantine         SettingsQuarantine         `json:"quarantine"`
+	History            SettingsHistory            `json:"history"`
+	Server             SettingsServer             `json:"server"`
+	DiscordBot         SettingsDiscordBot         `json:"discordBot"`
+	Metrics            SettingsMetrics            `json:"metrics"`
+	JSONLines          SettingsJSONLines          `json:"jsonLines"`
+	Log                SettingsLog                `json:"log"`
+	Proxy              SettingsProxy              `json:"proxy"`
 }
 
 func LoadConfig(file string) *Settings {
@@ -81,25 +243,25 @@ func (s *Settings) ReloadConfig() *Settings {
 
 func (s *Settings) LoadConfigFile(file string) bool {
 	if _, err := os.Stat(file); os.IsNotExist(err) {
-		log.Printf("Config file '%s' does not exist.\n", file)
+		logger.Warnf("Config file '%s' does not exist.\n", file)
 		return false
 	}
 
 	data, err := os.ReadFile(file)
 	if err != nil {
-		log.Printf("Error loading config file: %s\n", err.Error())
+		logger.Errorf("Unable to load config file: %s\n", err.Error())
 		return false
 	}
 
 	var settings Settings
 	if err := json.Unmarshal(data, &settings); err != nil {
-		log.Printf("Error unmarshal config file: %s\n", err.Error())
+		logger.Errorf("Unable to unmarshal config file: %s\n", err.Error())
 		return false
 	}
 
 	*s = settings
 
-	log.Printf("Loaded settings from config file '%s'\n", file)
+	logger.Infof("Loaded settings from config file '%s'\n", file)
 
 	return true
 }
@@ -111,13 +273,28 @@ func (s *Settings) LoadDefaultConfig() {
 		Exchange: "binance",
 		Refresh:  15,
 		AutoCoins: SettingsAutoCoins{
-			Max1hrPercent:  5,
-			Max4hrPercent:  5,
-			Max24hrPercent: 10,
-			CooldownHours:  4,
-			MinAthPercent:  5,
-			MinAge:         14,
+			Max1hrPercent:           5,
+			Max4hrPercent:           5,
+			Max24hrPercent:          10,
+			CooldownHours:           4,
+			MinAthPercent:           5,
+			MinAge:                  14,
+			MinQuoteVolume:          10000000,
+			MinTradeCount:           50000,
+			MaxVolumeSpike:          5,
+			VolumeSpikeMinutes:      15,
+			MaxFundingRate:          0.1,
+			MaxOpenInterest:         20,
+			OpenInterestHours:       4,
+			MaxVolatility:           8,
+			VolatilityCooldownHours: 4,
+		},
+		Rules: DefaultRules,
+		ThresholdOverrides: SettingsThresholdOverrides{
+			Groups:  []SettingsThresholdGroup{},
+			Symbols: map[string]map[string]float64{},
 		},
+		Schedules: []SettingsSchedule{},
 		Filters: SettingsFilters{
 			BlackList: []string{
 				"BTCUSDT", "ETHUSDT", "YFIUSDT", "DEFIUSDT", "DOGEUSDT",
@@ -135,6 +312,75 @@ func (s *Settings) LoadDefaultConfig() {
 		Discord: SettingsDiscord{
 			WebHook:        "",
 			MentionOnError: false,
+			Details:        false,
+		},
+		Telegram: SettingsTelegram{
+			Token:         "",
+			ChatID:        "",
+			Silent:        true,
+			NotifyOnError: true,
+		},
+		Outputs: SettingsOutputs{
+			Slack: SettingsSlack{
+				WebHook:        "",
+				MentionOnError: false,
+			},
+			Webhooks: []SettingsWebhook{},
+		},
+		MarketData: SettingsMarketData{
+			Stream: false,
+			Store:  false,
+		},
+		Quarantine: SettingsQuarantine{
+			MinMinutes:        0,
+			CleanRuns:         1,
+			ReleaseThresholds: map[string]float64{},
+		},
+		Server: SettingsServer{
+			Enabled: false,
+			Address: "127.0.0.1:8090",
+			Token:   "",
+		},
+		DiscordBot: SettingsDiscordBot{
+			Enabled:       false,
+			Address:       "127.0.0.1:8091",
+			ApplicationID: "",
+			PublicKey:     "",
+			Token:         "",
+			GuildID:       "",
+			Roles:         []string{},
+		},
+		Metrics: SettingsMetrics{
+			Enabled: false,
+			Address: "127.0.0.1:9101",
+		},
+		JSONLines: SettingsJSONLines{
+			Enabled:    false,
+			File:       "autocoins-runs.jsonl",
+			Symbols:    false,
+			MaxSizeMB:  10,
+			MaxBackups: 5,
+		},
+		Log: SettingsLog{
+			Level:      "info",
+			File:       "",
+			MaxSizeMB:  10,
+			Daily:      false,
+			MaxBackups: 5,
+		},
+		History: SettingsHistory{
+			Enabled:       true,
+			RetentionDays: 90,
+		},
+		Regime: SettingsRegime{
+			Enabled: false,
+			Bands: []SettingsRegimeBand{
+				{MinSwing: 50, Multiplier: 1.25},
+				{MinSwing: 75, Multiplier: 1.5},
+			},
+			CorrelationSymbol:   "BTCUSDT",
+			MinCorrelation:      0.3,
+			DecoupledMultiplier: 0.75,
 		},
 		Proxy: SettingsProxy{
 			Address:  "",
@@ -143,7 +389,7 @@ func (s *Settings) LoadDefaultConfig() {
 		},
 	}
 
-	log.Println("Using default settings")
+	logger.Infof("Using default settings")
 }
 
 func (s *Settings) ValidateSettings() {
@@ -151,12 +397,109 @@ func (s *Settings) ValidateSettings() {
 		s.Refresh = 1
 	}
 	if s.API == "" {
-		log.Fatal("No API URL set in config file.")
+		logger.Fatalf("No API URL set in config file.")
+	}
+	s.Exchange = strings.ToLower(s.Exchange)
+	if s.Exchange == "" {
+		s.Exchange = "binance"
+	}
+	if s.Exchange != "binance" && s.Exchange != "bybit" {
+		logger.Fatalf("Unsupported exchange '%s' in config file (use binance or bybit).\n", s.Exchange)
+	}
+	if len(s.Rules) == 0 {
+		s.Rules = DefaultRules
+	}
+	if s.AutoCoins.VolumeSpikeMinutes < 1 {
+		s.AutoCoins.VolumeSpikeMinutes = 15
+	}
+	if s.AutoCoins.OpenInterestHours < 1 {
+		s.AutoCoins.OpenInterestHours = 4
+	}
+	if s.AutoCoins.VolatilityCooldownHours < 1 {
+		s.AutoCoins.VolatilityCooldownHours = 4
+	}
+	if s.Regime.Enabled {
+		for _, b := range s.Regime.Bands {
+			if b.Multiplier <= 0 {
+				logger.Fatalf("Invalid regime band multiplier %v in config file.\n", b.Multiplier)
+			}
+		}
+		if s.Regime.CorrelationSymbol != "" && s.Regime.DecoupledMultiplier <= 0 {
+			logger.Fatalf("Invalid regime decoupledMultiplier %v in config file.\n", s.Regime.DecoupledMultiplier)
+		}
+	}
+	for _, rule := range s.Rules {
+		if !IsRule(rule) {
+			logger.Fatalf("Unknown rule '%s' in config file.\n", rule)
+		}
+	}
+	s.ThresholdOverrides.validate()
+	for _, schedule := range s.Schedules {
+		if err := schedule.validate(); err != nil {
+			logger.Fatalf("Invalid schedule '%s' in config file: %s\n", schedule.Name, err.Error())
+		}
+	}
+	for i, webhook := range s.Outputs.Webhooks {
+		if webhook.Name == "" {
+			s.Outputs.Webhooks[i].Name = fmt.Sprintf("webhook%d", i+1)
+		}
+		if webhook.URL == "" {
+			logger.Fatalf("No url set for webhook '%s' in config file.\n", s.Outputs.Webhooks[i].Name)
+		}
+		if _, err := NewWebhookOutputWriter(s.Outputs.Webhooks[i], ""); err != nil {
+			logger.Fatalf("Invalid template of webhook '%s' in config file: %s\n", s.Outputs.Webhooks[i].Name, err.Error())
+		}
+	}
+	if s.Server.Address == "" {
+		s.Server.Address = "127.0.0.1:8090"
+	}
+	if s.DiscordBot.Address == "" {
+		s.DiscordBot.Address = "127.0.0.1:8091"
+	}
+	if s.DiscordBot.Enabled && s.DiscordBot.PublicKey == "" {
+		logger.Fatalf("No publicKey set for the Discord bot in config file.\n")
+	}
+	if s.Metrics.Address == "" {
+		s.Metrics.Address = "127.0.0.1:9101"
+	}
+	if s.Log.Level == "" {
+		s.Log.Level = "info"
+	}
+	if _, err := logger.ParseLevel(s.Log.Level); err != nil {
+		logger.Fatalf("Invalid log level in config file: %s\n", err.Error())
+	}
+	if s.Log.MaxSizeMB < 0 {
+		s.Log.MaxSizeMB = 0
+	}
+	if s.JSONLines.File == "" {
+		s.JSONLines.File = "autocoins-runs.jsonl"
+	}
+	if s.JSONLines.MaxSizeMB < 0 {
+		s.JSONLines.MaxSizeMB = 0
+	}
+	if s.History.RetentionDays < 0 {
+		s.History.RetentionDays = 0
+	}
+	if s.Quarantine.MinMinutes < 0 {
+		s.Quarantine.MinMinutes = 0
+	}
+	if s.Quarantine.CleanRuns < 1 {
+		s.Quarantine.CleanRuns = 1
+	}
+	for rule := range s.Quarantine.ReleaseThresholds {
+		if !IsRule(rule) {
+			logger.Fatalf("Unknown rule '%s' in quarantine releaseThresholds in config file.\n", rule)
+		}
 	}
 
 	s.Filters.WickHunterDB = true
 }
 
+// HasRule returns true when the rule is enabled.
+func (s *Settings) HasRule(name string) bool {
+	return ContainsString(s.Rules, name)
+}
+
 func (s *Settings) PostProcess() {
 	sort.Strings(s.Filters.BlackList)
 	sort.Strings(s.Filters.ExcludeList)
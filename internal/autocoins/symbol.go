@@ -4,14 +4,15 @@ import (
 	"math"
 	"time"
 
-	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
 )
 
 type ExchangeData struct {
-	Prices24Hours *[]binance.Ticker
-	Kline1Minute  []binance.KLine
-	Kline1Month   []binance.KLine
-	Candles       int
+	Shared       *SharedData
+	Kline1Minute []exchange.Kline
+	Kline1Month  []exchange.Kline
+	OpenInterest []exchange.OpenInterest
+	Candles      int
 }
 
 type SymbolDataValues struct {
@@ -20,61 +21,89 @@ type SymbolDataValues struct {
 	Percent24Hour float64   `json:"perc24hrVal"`
 	AllTimeHigh   float64   `json:"AthVal"`
 	Age           int       `json:"AgeVal"`
-}
-
-type SymbolDataResult struct {
-	Percent1Hour  bool `json:"perc1hr"`
-	Percent4Hour  bool `json:"perc4hr"`
-	Percent24Hour bool `json:"perc24hr"`
-	AllTimeHigh   bool `json:"Ath"`
-	Age           bool `json:"Age"`
+	QuoteVolume   float64   `json:"quoteVolumeVal"`
+	TradeCount    int64     `json:"tradeCountVal"`
+	VolumeSpike   float64   `json:"volumeSpikeVal"`
+	FundingRate   float64   `json:"fundingRateVal"`
+	OpenInterest  float64   `json:"openInterestVal"`
+	Volatility    []float64 `json:"volatilityVal"`
+	Correlation   float64   `json:"correlationVal"`
 }
 
 type SymbolDataObject struct {
-	Symbol    binance.Symbol   `json:"symbol"`
-	Open      bool             `json:"Open"`
-	Time      time.Time        `json:"dateTime"`
-	APIFailed bool             `json:"apiFailed"`
-	Excluded  bool             `json:"excluded"`
-	Values    SymbolDataValues `json:"values"`
-	Result    SymbolDataResult `json:"result"`
-	data      ExchangeData
-	settings  *SettingsAutoCoins
+	Symbol      exchange.Symbol      `json:"symbol"`
+	Open        bool                 `json:"Open"`
+	Time        time.Time            `json:"dateTime"`
+	APIFailed   bool                 `json:"apiFailed"`
+	Excluded    bool                 `json:"excluded"`
+	Values      SymbolDataValues     `json:"values"`
+	Results     []RuleResult         `json:"results"`
+	Multipliers ThresholdMultipliers `json:"multipliers"`
+	Overrides   []string             `json:"overrides"` // Overrides the names of the threshold overrides used, see applyThresholdOverrides.
+	Held        bool                 `json:"held"`      // Held passes the rules but stays quarantined, see applyHysteresis.
+	HeldReason  string               `json:"heldReason"`
+	data        ExchangeData
+	settings    *SettingsAutoCoins
+	rules       []Rule
 }
 
-func (a *AutoCoins) RetrieveSymbolData(symbol binance.Symbol, prices24Hours *[]binance.Ticker, c chan SymbolDataObject) {
+// priceHours returns the number of hours of 1 minute klines used for the 1hr and 4hr price changes.
+func (a *AutoCoins) priceHours() int {
 	minCandles := 4
 	if a.Settings.AutoCoins.CooldownHours >= 4 {
 		minCandles = a.Settings.AutoCoins.CooldownHours
 	}
-	dateTime := time.Now()
-	limit := minCandles * 60
-	kline1Minute, err := a.ExchangeAPI.GetKLine(symbol, limit, binance.OneMinute)
+	return minCandles
+}
+
+// klineHours returns the number of hours of 1 minute klines needed for the calculations.
+func (a *AutoCoins) klineHours() int {
+	minCandles := a.priceHours()
+	if a.Settings.AutoCoins.VolatilityCooldownHours > minCandles {
+		minCandles = a.Settings.AutoCoins.VolatilityCooldownHours
+	}
+	return minCandles
+}
+
+func (a *AutoCoins) RetrieveSymbolData(symbol exchange.Symbol, shared *SharedData, c chan SymbolDataObject) {
+	dateTime := a.now()
+	limit := a.klineHours() * 60
+	kline1Minute, err := a.ExchangeAPI.GetKline(symbol, exchange.OneMinute, limit)
 	if err != nil {
 		c <- a.apiFailResult(symbol)
 		return
 	}
 
-	start := time.Unix(symbol.OnboardDate/1000, 0)
-	age := time.Since(start).Hours() / 24.0
+	age := dateTime.Sub(symbol.OnboardDate).Hours() / 24.0
 	limit2 := math.Round((age / 30) + 1)
 
-	kline1Month, err := a.ExchangeAPI.GetKLine(symbol, int(limit2), binance.OneMonth)
+	kline1Month, err := a.ExchangeAPI.GetKline(symbol, exchange.OneMonth, int(limit2))
 	if err != nil {
 		c <- a.apiFailResult(symbol)
 		return
 	}
 
+	var openInterest []exchange.OpenInterest
+	if a.DerivativesAPI != nil && a.Settings.HasRule("openInterest") {
+		openInterest, err = a.DerivativesAPI.GetOpenInterestHistory(symbol, a.Settings.AutoCoins.OpenInterestHours)
+		if err != nil {
+			c <- a.apiFailResult(symbol)
+			return
+		}
+	}
+
 	object := SymbolDataObject{
 		Symbol: symbol,
 		Time:   dateTime,
 		data: ExchangeData{
-			Prices24Hours: prices24Hours,
-			Kline1Minute:  kline1Minute,
-			Kline1Month:   kline1Month,
-			Candles:       minCandles,
+			Shared:       shared,
+			Kline1Minute: kline1Minute,
+			Kline1Month:  kline1Month,
+			OpenInterest: openInterest,
+			Candles:      a.priceHours(),
 		},
 		settings: &a.Settings.AutoCoins,
+		rules:    createRules(a.Settings.Rules, &a.Settings.AutoCoins),
 		Values: SymbolDataValues{
 			Age: int(age),
 		},
@@ -84,11 +113,14 @@ func (a *AutoCoins) RetrieveSymbolData(symbol binance.Symbol, prices24Hours *[]b
 }
 
 func (s *SymbolDataObject) Calculate() {
-	prices1Hour, err := binance.Get1HourPrices(s.data.Kline1Minute)
-	if err != nil {
+	// The klines can span more hours than the price changes need (volatility cooldown), use the most recent ones.
+	prices1Hour := exchange.OpenPrices(s.data.Kline1Minute)
+	if len(prices1Hour) < s.data.Candles*60 || len(prices1Hour) < 240 {
 		s.APIFailed = true
 		return
 	}
+	prices4Hour := prices1Hour[len(prices1Hour)-240:]
+	prices1Hour = prices1Hour[len(prices1Hour)-s.data.Candles*60:]
 	percent1Hour := []float64{}
 	for i := 1; i < s.data.Candles+1; i++ {
 		end := i*60 - 1
@@ -100,60 +132,67 @@ func (s *SymbolDataObject) Calculate() {
 		percent := ((prices1Hour[end] - prices1Hour[start]) * 100) / prices1Hour[end]
 		percent1Hour = append(percent1Hour, percent)
 	}
-	current4HoursPercent := ((prices1Hour[239] - prices1Hour[0]) * 100) / prices1Hour[239]
-	current24HoursPercent := binance.CalculateCurrent24HourPercent(*s.data.Prices24Hours, s.Symbol.Name)
+	current4HoursPercent := ((prices4Hour[239] - prices4Hour[0]) * 100) / prices4Hour[239]
+	current24HoursPercent := 0.0
+	if ticker, ok := exchange.FindTicker(s.data.Shared.Prices24Hours, s.Symbol.Name); ok {
+		current24HoursPercent = ticker.PriceChangePercent24h
+		s.Values.QuoteVolume = ticker.QuoteVolume
+		s.Values.TradeCount = ticker.Count
+	}
+	s.Values.VolumeSpike = VolumeSpike(s.data.Kline1Minute, s.settings.VolumeSpikeMinutes)
+	if rate, ok := exchange.FindFundingRate(s.data.Shared.FundingRates, s.Symbol.Name); ok {
+		s.Values.FundingRate = rate.Rate * 100
+	}
+	s.Values.OpenInterest = OpenInterestChange(s.data.OpenInterest)
+	s.Values.Volatility = HourlyRange(s.data.Kline1Minute, s.settings.VolatilityCooldownHours)
+	if s.data.Shared.CorrelationKlines != nil {
+		s.Values.Correlation = Correlation(s.data.Kline1Minute, s.data.Shared.CorrelationKlines)
+	}
 
 	// Get age and max all time high
-	ath := binance.GetMaximumAllTimeHigh(s.data.Kline1Month)
+	ath := exchange.MaximumHigh(s.data.Kline1Month)
 	currentPercentageATH := ((ath - prices1Hour[len(prices1Hour)-1]) * 100 / ath)
 
-	s.calculateResults(percent1Hour, current4HoursPercent, current24HoursPercent, currentPercentageATH)
+	s.calculateValues(percent1Hour, current4HoursPercent, current24HoursPercent, currentPercentageATH)
+	s.checkRules()
 }
 
-func (s *SymbolDataObject) calculateResults(percent1Hour []float64, current4HoursPercent float64, current24HoursPercent float64, currentPercentageATH float64) {
+func (s *SymbolDataObject) calculateValues(percent1Hour []float64, current4HoursPercent float64, current24HoursPercent float64, currentPercentageATH float64) {
 	// 1 hour percent
 	x := s.settings.CooldownHours - 1
-	values1HrPercent := percent1Hour[:x]
-	max := 0.0
-	for _, val := range values1HrPercent {
-		v := math.Abs(val)
-		if v > max {
-			max = v
-		}
-	}
-	result1HrPercent := max < float64(s.settings.Max1hrPercent)
-
-	// 4 hour percent
-	result4HrPercent := math.Abs(current4HoursPercent) < float64(s.settings.Max4hrPercent)
-	values4HrPercent := current4HoursPercent
-
-	// 24 hour percent
-	result24HrPercent := math.Abs(current24HoursPercent) < float64(s.settings.Max24hrPercent)
-	values24HrPercent := current24HoursPercent
-
-	resultAth := currentPercentageATH > float64(s.settings.MinAthPercent)
-	valuesAth := currentPercentageATH
-
-	resultAge := s.Values.Age > s.settings.MinAge
-
-	s.Result.Percent1Hour = result1HrPercent
-	s.Values.Percent1Hour = values1HrPercent
-	s.Result.Percent4Hour = result4HrPercent
-	s.Values.Percent4Hour = values4HrPercent
-	s.Result.Percent24Hour = result24HrPercent
-	s.Values.Percent24Hour = values24HrPercent
-	s.Result.AllTimeHigh = resultAth
-	s.Values.AllTimeHigh = valuesAth
-	s.Result.Age = resultAge
+	s.Values.Percent1Hour = percent1Hour[:x]
+	s.Values.Percent4Hour = current4HoursPercent
+	s.Values.Percent24Hour = current24HoursPercent
+	s.Values.AllTimeHigh = currentPercentageATH
 	s.Open = false
 	s.APIFailed = false
 }
 
+// checkRules checks all the quarantine rules.
+func (s *SymbolDataObject) checkRules() {
+	s.Results = make([]RuleResult, 0, len(s.rules))
+	for _, rule := range s.rules {
+		s.Results = append(s.Results, rule.Check(s))
+	}
+}
+
+// ShouldQuarantine returns true when one of the rules did not pass or the symbol is held in quarantine.
 func (s *SymbolDataObject) ShouldQuarantine() bool {
-	return !s.Result.Percent1Hour || !s.Result.Percent24Hour || !s.Result.Percent4Hour || !s.Result.AllTimeHigh || !s.Result.Age
+	return s.Held || len(s.FailedRules()) > 0
+}
+
+// FailedRules returns the results of the rules that did not pass.
+func (s *SymbolDataObject) FailedRules() []RuleResult {
+	failed := []RuleResult{}
+	for _, r := range s.Results {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
 }
 
-func (a *AutoCoins) apiFailResult(symbol binance.Symbol) SymbolDataObject {
+func (a *AutoCoins) apiFailResult(symbol exchange.Symbol) SymbolDataObject {
 	return SymbolDataObject{
 		Symbol:    symbol,
 		APIFailed: true,
@@ -0,0 +1,150 @@
+package autocoins
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+// stubExchange serves fixed market data for every symbol.
+type stubExchange struct {
+	symbols     []exchange.Symbol
+	tickers     []exchange.Ticker
+	kline1m     []exchange.Kline
+	kline1M     []exchange.Kline
+	failSymbols []string
+}
+
+func (e *stubExchange) GetSymbols() ([]exchange.Symbol, error) { return e.symbols, nil }
+func (e *stubExchange) GetTickers() ([]exchange.Ticker, error) { return e.tickers, nil }
+func (e *stubExchange) RateLimitChecks(symbolCount int)        {}
+func (e *stubExchange) Weight() exchange.Weight                { return exchange.Weight{} }
+func (e *stubExchange) Cancel()                                {}
+
+func (e *stubExchange) GetKline(symbol exchange.Symbol, interval exchange.KlineInterval, limit int) ([]exchange.Kline, error) {
+	if ContainsString(e.failSymbols, symbol.Name) {
+		return nil, errors.New("stub failure")
+	}
+	klines := e.kline1m
+	if interval == exchange.OneMonth {
+		klines = e.kline1M
+	}
+	if len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+	return klines, nil
+}
+
+// flatKlines returns count klines with the same open price.
+func flatKlines(count int, price float64) []exchange.Kline {
+	klines := make([]exchange.Kline, count)
+	start := time.Now().Add(-time.Duration(count) * time.Minute)
+	for i := range klines {
+		klines[i] = exchange.Kline{
+			OpenTime: start.Add(time.Duration(i) * time.Minute),
+			Open:     price,
+			High:     price,
+			Low:      price,
+			Close:    price,
+		}
+	}
+	return klines
+}
+
+func newStubAutoCoins(e *stubExchange) *AutoCoins {
+	return &AutoCoins{
+		Settings: Settings{
+			AutoCoins: SettingsAutoCoins{
+				Max1hrPercent:  5,
+				Max4hrPercent:  5,
+				Max24hrPercent: 10,
+				CooldownHours:  4,
+				MinAthPercent:  5,
+				MinAge:         14,
+			},
+			Rules: DefaultRules,
+		},
+		ExchangeAPI: e,
+	}
+}
+
+func TestRetrieveSymbolData(t *testing.T) {
+	e := &stubExchange{
+		tickers: []exchange.Ticker{{Symbol: "TEST", PriceChangePercent24h: 2}},
+		kline1m: flatKlines(240, 10),
+		kline1M: []exchange.Kline{{Open: 20, High: 20}},
+	}
+	a := newStubAutoCoins(e)
+	symbol := exchange.Symbol{Name: "TEST", OnboardDate: time.Now().AddDate(0, -2, 0)}
+
+	c := make(chan SymbolDataObject, 1)
+	a.RetrieveSymbolData(symbol, &SharedData{Prices24Hours: e.tickers}, c)
+	object := <-c
+
+	if object.APIFailed {
+		t.Fatalf("retrieve symbol data failed")
+	}
+	if object.ShouldQuarantine() {
+		t.Errorf("symbol should not be quarantined: %+v", object.FailedRules())
+	}
+	if object.Values.Percent24Hour != 2 {
+		t.Errorf("invalid 24hr percent: expected %v got %v", 2.0, object.Values.Percent24Hour)
+	}
+	if object.Values.AllTimeHigh != 50 {
+		t.Errorf("invalid ath percent: expected %v got %v", 50.0, object.Values.AllTimeHigh)
+	}
+}
+
+func TestRetrieveSymbolDataFailed(t *testing.T) {
+	e := &stubExchange{
+		kline1m:     flatKlines(240, 10),
+		failSymbols: []string{"TEST"},
+	}
+	a := newStubAutoCoins(e)
+
+	c := make(chan SymbolDataObject, 1)
+	a.RetrieveSymbolData(exchange.Symbol{Name: "TEST"}, &SharedData{}, c)
+	object := <-c
+
+	if !object.APIFailed {
+		t.Errorf("expected api failure for symbol")
+	}
+}
+
+func TestRetrieveSymbolDataVolatilityCooldown(t *testing.T) {
+	// Flat for 4 hours, then rising 1% per hour for the last 4 hours.
+	klines := flatKlines(480, 10)
+	for i := 240; i < len(klines); i++ {
+		klines[i].Open = 10 + float64(i-239)*0.1/60
+	}
+	e := &stubExchange{
+		tickers: []exchange.Ticker{{Symbol: "TEST"}},
+		kline1m: klines,
+		kline1M: []exchange.Kline{{Open: 20, High: 20}},
+	}
+	a := newStubAutoCoins(e)
+	a.Settings.AutoCoins.VolatilityCooldownHours = 8
+	symbol := exchange.Symbol{Name: "TEST", OnboardDate: time.Now().AddDate(0, -2, 0)}
+
+	c := make(chan SymbolDataObject, 1)
+	a.RetrieveSymbolData(symbol, &SharedData{Prices24Hours: e.tickers}, c)
+	object := <-c
+
+	if object.APIFailed {
+		t.Fatalf("retrieve symbol data failed")
+	}
+	last := klines[len(klines)-1].Open
+	expected := (last - klines[240].Open) * 100 / last
+	if math.Abs(object.Values.Percent4Hour-expected) > 1e-9 {
+		t.Errorf("invalid 4hr percent: expected %v got %v", expected, object.Values.Percent4Hour)
+	}
+	if len(object.Values.Percent1Hour) != 3 || object.Values.Percent1Hour[0] <= 0 {
+		t.Errorf("invalid 1hr percent: expected 3 rising hours got %v", object.Values.Percent1Hour)
+	}
+	if len(object.Values.Volatility) != 8 {
+		t.Errorf("invalid volatility hours: expected %d got %d", 8, len(object.Values.Volatility))
+	}
+}
@@ -0,0 +1,63 @@
+package autocoins
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LompeBoer/go-autocoins/internal/telegram"
+)
+
+// TelegramOutputWriter sends output to a Telegram chat using the Bot API.
+type TelegramOutputWriter struct {
+	Version       string
+	Bot           *telegram.Bot
+	Silent        bool // Silent sends the results without a notification sound.
+	NotifyOnError bool // NotifyOnError sends the errors with a notification sound.
+}
+
+func (w *TelegramOutputWriter) WriteResult(result *RunResult) error {
+	return w.Bot.SendMarkdown(w.formatResult(result), w.Silent)
+}
+
+func (w *TelegramOutputWriter) WriteError(message string) error {
+	return w.Bot.SendError(message, !w.NotifyOnError)
+}
+
+// formatResult renders the market swing report and the quarantine lists as MarkdownV2.
+func (w *TelegramOutputWriter) formatResult(result *RunResult) string {
+	e := telegram.Escape
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "*AutoCoins MarketSwing report*\n")
+	fmt.Fprintf(&b, "_%s_\n", e(fmt.Sprintf("Generated %s (using v%s)", result.Time.Format("2006-01-02 15:04"), w.Version)))
+	if result.Schedule != "" {
+		fmt.Fprintf(&b, "Schedule: %s\n", e(result.Schedule))
+	}
+
+	for _, m := range result.MarketSwings {
+		fmt.Fprintf(&b, "\n*%s*\n", e(fmt.Sprintf("Last %s - %s", m.Timeframe, m.SwingMood)))
+		fmt.Fprintf(&b, "%s\n", e(fmt.Sprintf("%.0f%% Long | %d Coins | Avg %.2f%% | Max %.2f%% %s", m.Positive.Percent, m.Positive.CoinCount, m.Positive.Average, m.Positive.Max, m.Positive.MaxCoin)))
+		fmt.Fprintf(&b, "%s\n", e(fmt.Sprintf("%.0f%% Short | %d Coins | Avg %.2f%% | Max %.2f%% %s", m.Negative.Percent, m.Negative.CoinCount, m.Negative.Average, m.Negative.Max, m.Negative.MaxCoin)))
+	}
+
+	q := result.Messages
+	if q == nil {
+		return b.String()
+	}
+	list := func(name string, value string, always bool) {
+		if value == "" && !always {
+			return
+		}
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(&b, "\n*%s*\n%s\n", e(name), e(value))
+	}
+	list("New quarantined", q.NewQuarantined, false)
+	list("Quarantined", q.Quarantined, true)
+	list("Unquarantined", q.Unquarantined, false)
+	list("Open positions - not quarantined", q.OpenPositions, false)
+	list("Excluded - not quarantined", q.Excluded, false)
+	list("Failed to process", q.Failed, false)
+
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package autocoins
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/telegram"
+)
+
+func TestTelegramOutputWriter(t *testing.T) {
+	messages := []telegram.SendMessageRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m telegram.SendMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			t.Errorf("invalid body: %s", err.Error())
+		}
+		messages = append(messages, m)
+		json.NewEncoder(w).Encode(telegram.Response{OK: true})
+	}))
+	defer server.Close()
+
+	bot := telegram.NewBot("TOKEN", "-100")
+	bot.BaseURL = server.URL
+	w := &TelegramOutputWriter{Version: "1.0.0", Bot: bot, Silent: true, NotifyOnError: true}
+
+	result := &RunResult{
+		Time:         time.Date(2021, 6, 4, 12, 0, 0, 0, time.UTC),
+		MarketSwings: []MarketSwing{{Timeframe: "1hr", SwingMood: "20% Bullish"}},
+		Messages:     &QuarantineMessages{NewQuarantined: "AAAUSDT", Quarantined: "AAAUSDT, BBBUSDT"},
+	}
+	if err := w.WriteResult(result); err != nil {
+		t.Fatalf("WriteResult returned error: %s", err.Error())
+	}
+	if len(messages) != 1 {
+		t.Fatalf("invalid number of messages: expected %d got %d", 1, len(messages))
+	}
+	text := messages[0].Text
+	for _, expected := range []string{"*AutoCoins MarketSwing report*", `*Last 1hr \- 20% Bullish*`, "*New quarantined*\nAAAUSDT", "*Quarantined*\nAAAUSDT, BBBUSDT"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("message does not contain %q: got %s", expected, text)
+		}
+	}
+	if strings.Contains(text, "Unquarantined") {
+		t.Errorf("message contains an empty list: got %s", text)
+	}
+	if !messages[0].DisableNotification {
+		t.Errorf("expected a silent result message")
+	}
+
+	if err := w.WriteError("unable to connect"); err != nil {
+		t.Fatalf("WriteError returned error: %s", err.Error())
+	}
+	if len(messages) != 2 || messages[1].DisableNotification {
+		t.Errorf("expected an error message with notification: got %+v", messages)
+	}
+}
@@ -0,0 +1,144 @@
+package autocoins
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// applyThresholdOverrides replaces the thresholds of the symbols that match a group, pattern or symbol override
+// and checks the rules again. The names of the matched overrides are stored in the symbol.
+func (a *AutoCoins) applyThresholdOverrides(objects []SymbolDataObject) {
+	overrides := a.Settings.ThresholdOverrides
+	if len(overrides.Groups) == 0 && len(overrides.Symbols) == 0 {
+		return
+	}
+	ranks := volumeRanks(objects)
+
+	for i := range objects {
+		object := &objects[i]
+		if object.APIFailed {
+			continue
+		}
+		thresholds, names := overrides.thresholds(object.Symbol.Name, ranks[object.Symbol.Name])
+		if len(names) == 0 {
+			continue
+		}
+		object.Overrides = names
+		object.rules = withThresholds(object.rules, thresholds)
+		object.checkRules()
+	}
+}
+
+// thresholds returns the combined thresholds of the overrides that match the symbol and the names of those overrides.
+func (s *SettingsThresholdOverrides) thresholds(symbol string, rank int) (map[string]float64, []string) {
+	thresholds := map[string]float64{}
+	names := []string{}
+	apply := func(name string, values map[string]float64) {
+		for rule, value := range values {
+			thresholds[rule] = value
+		}
+		names = append(names, name)
+	}
+
+	for _, g := range s.Groups {
+		if g.contains(symbol, rank) {
+			apply(g.Name, g.Thresholds)
+		}
+	}
+	patterns := make([]string, 0, len(s.Symbols))
+	for pattern := range s.Symbols {
+		if isPattern(pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if matchSymbol(pattern, symbol) {
+			apply(pattern, s.Symbols[pattern])
+		}
+	}
+	if values, ok := s.Symbols[symbol]; ok {
+		apply(symbol, values)
+	}
+	return thresholds, names
+}
+
+// contains returns true when the symbol matches one of the symbols of the group or is within the volume rank range.
+func (g *SettingsThresholdGroup) contains(symbol string, rank int) bool {
+	for _, pattern := range g.Symbols {
+		if matchSymbol(pattern, symbol) {
+			return true
+		}
+	}
+	if g.MaxVolumeRank > 0 && rank > 0 {
+		return rank >= g.MinVolumeRank && rank <= g.MaxVolumeRank
+	}
+	return false
+}
+
+func (s *SettingsThresholdOverrides) validate() {
+	validateThresholds := func(name string, thresholds map[string]float64) {
+		for rule := range thresholds {
+			if !IsRule(rule) {
+				logger.Fatalf("Unknown rule '%s' in thresholdOverrides '%s' in config file.\n", rule, name)
+			}
+		}
+	}
+	validatePattern := func(pattern string) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			logger.Fatalf("Invalid symbol pattern '%s' in thresholdOverrides in config file.\n", pattern)
+		}
+	}
+
+	for i := range s.Groups {
+		g := &s.Groups[i]
+		if g.Name == "" {
+			logger.Fatalf("Missing group name in thresholdOverrides in config file.")
+		}
+		if g.MinVolumeRank < 1 {
+			g.MinVolumeRank = 1
+		}
+		for _, pattern := range g.Symbols {
+			validatePattern(pattern)
+		}
+		validateThresholds(g.Name, g.Thresholds)
+	}
+	for pattern, thresholds := range s.Symbols {
+		validatePattern(pattern)
+		validateThresholds(pattern, thresholds)
+	}
+}
+
+// volumeRanks returns the rank by 24hr quote volume of the symbols, 1 is the highest volume.
+func volumeRanks(objects []SymbolDataObject) map[string]int {
+	list := []SymbolDataObject{}
+	for _, object := range objects {
+		if !object.APIFailed {
+			list = append(list, object)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Values.QuoteVolume > list[j].Values.QuoteVolume })
+
+	ranks := map[string]int{}
+	for i, object := range list {
+		ranks[object.Symbol.Name] = i + 1
+	}
+	return ranks
+}
+
+// isPattern returns true when the value is a glob pattern instead of a symbol name.
+func isPattern(value string) bool {
+	return strings.ContainsAny(value, "*?[")
+}
+
+// matchSymbol returns true when the symbol is equal to or matches the glob pattern.
+func matchSymbol(pattern string, symbol string) bool {
+	if !isPattern(pattern) {
+		return pattern == symbol
+	}
+	matched, _ := path.Match(pattern, symbol)
+	return matched
+}
@@ -0,0 +1,77 @@
+package autocoins
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func TestThresholdOverrides(t *testing.T) {
+	overrides := SettingsThresholdOverrides{
+		Groups: []SettingsThresholdGroup{
+			{Name: "majors", MinVolumeRank: 1, MaxVolumeRank: 2, Thresholds: map[string]float64{"1hr": 8, "4hr": 8}},
+			{Name: "memes", Symbols: []string{"DOGEUSDT", "*SHIB*"}, Thresholds: map[string]float64{"1hr": 3}},
+		},
+		Symbols: map[string]map[string]float64{
+			"1000*":        {"4hr": 12},
+			"1000SHIBUSDT": {"1hr": 4},
+		},
+	}
+
+	tests := []struct {
+		symbol     string
+		rank       int
+		thresholds map[string]float64
+		names      []string
+	}{
+		{"XYZUSDT", 10, map[string]float64{}, []string{}},
+		{"ABCUSDT", 2, map[string]float64{"1hr": 8, "4hr": 8}, []string{"majors"}},
+		{"DOGEUSDT", 1, map[string]float64{"1hr": 3, "4hr": 8}, []string{"majors", "memes"}},
+		{"1000SHIBUSDT", 0, map[string]float64{"1hr": 4, "4hr": 12}, []string{"memes", "1000*", "1000SHIBUSDT"}},
+	}
+	for _, test := range tests {
+		thresholds, names := overrides.thresholds(test.symbol, test.rank)
+		if strings.Join(names, ",") != strings.Join(test.names, ",") {
+			t.Errorf("%s: invalid overrides: expected %v got %v", test.symbol, test.names, names)
+		}
+		if len(thresholds) != len(test.thresholds) {
+			t.Errorf("%s: invalid thresholds: expected %v got %v", test.symbol, test.thresholds, thresholds)
+			continue
+		}
+		for rule, value := range test.thresholds {
+			if thresholds[rule] != value {
+				t.Errorf("%s: invalid %s threshold: expected %v got %v", test.symbol, rule, value, thresholds[rule])
+			}
+		}
+	}
+}
+
+func TestApplyThresholdOverrides(t *testing.T) {
+	settings := SettingsAutoCoins{Max1hrPercent: 5}
+	a := AutoCoins{Settings: Settings{
+		ThresholdOverrides: SettingsThresholdOverrides{
+			Groups: []SettingsThresholdGroup{
+				{Name: "top", MinVolumeRank: 1, MaxVolumeRank: 1, Thresholds: map[string]float64{"1hr": 8}},
+			},
+		},
+	}}
+	object := func(name string, quoteVolume float64) SymbolDataObject {
+		o := SymbolDataObject{
+			Symbol: exchange.Symbol{Name: name},
+			Values: SymbolDataValues{Percent1Hour: []float64{6}, QuoteVolume: quoteVolume},
+			rules:  createRules([]string{"1hr"}, &settings),
+		}
+		o.checkRules()
+		return o
+	}
+	objects := []SymbolDataObject{object("SMALLUSDT", 1000), object("LARGEUSDT", 5000)}
+	a.applyThresholdOverrides(objects)
+
+	if !objects[0].ShouldQuarantine() || objects[0].Results[0].Threshold != 5 || len(objects[0].Overrides) != 0 {
+		t.Errorf("SMALLUSDT: expected quarantine using threshold 5 got %+v", objects[0].Results)
+	}
+	if objects[1].ShouldQuarantine() || objects[1].Results[0].Threshold != 8 || len(objects[1].Overrides) != 1 {
+		t.Errorf("LARGEUSDT: expected permitted using threshold 8 got %+v %v", objects[1].Results, objects[1].Overrides)
+	}
+}
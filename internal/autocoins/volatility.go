@@ -0,0 +1,26 @@
+package autocoins
+
+import "github.com/LompeBoer/go-autocoins/internal/exchange"
+
+// HourlyRange returns the range between the highest high and the lowest low in percent
+// for each of the last `hours` hours of the 1 minute klines, the last hour is last.
+// A coin that whipsaws within an hour has a large range even when it ends flat.
+func HourlyRange(klines []exchange.Kline, hours int) []float64 {
+	ranges := []float64{}
+	for end := len(klines); end >= 60 && len(ranges) < hours; end -= 60 {
+		high, low := klines[end-60].High, klines[end-60].Low
+		for _, k := range klines[end-60 : end] {
+			if k.High > high {
+				high = k.High
+			}
+			if k.Low < low {
+				low = k.Low
+			}
+		}
+		if low <= 0 {
+			continue
+		}
+		ranges = append([]float64{(high - low) * 100 / low}, ranges...)
+	}
+	return ranges
+}
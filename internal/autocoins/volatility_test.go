@@ -0,0 +1,38 @@
+package autocoins
+
+import (
+	"testing"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func TestHourlyRange(t *testing.T) {
+	klines := make([]exchange.Kline, 180)
+	for i := range klines {
+		klines[i].High = 101
+		klines[i].Low = 99
+	}
+	// Whipsaw in the last hour that ends flat.
+	klines[150].High = 108
+	klines[170].Low = 92
+
+	ranges := HourlyRange(klines, 2)
+	if len(ranges) != 2 {
+		t.Fatalf("invalid range count: expected %d got %d", 2, len(ranges))
+	}
+	if ranges[0] != float64(2)*100/99 {
+		t.Errorf("invalid range: expected %v got %v", float64(2)*100/99, ranges[0])
+	}
+	if ranges[1] != float64(16)*100/92 {
+		t.Errorf("invalid range: expected %v got %v", float64(16)*100/92, ranges[1])
+	}
+
+	object := SymbolDataObject{
+		Values: SymbolDataValues{Volatility: ranges},
+		rules:  createRules([]string{"volatility"}, &SettingsAutoCoins{MaxVolatility: 8}),
+	}
+	object.checkRules()
+	if !object.ShouldQuarantine() {
+		t.Errorf("expected quarantine for %v", ranges)
+	}
+}
@@ -0,0 +1,35 @@
+package autocoins
+
+import "github.com/LompeBoer/go-autocoins/internal/exchange"
+
+// VolumeSpike returns the average volume of the last `minutes` klines divided by the average volume of the klines before.
+// Returns 0 when there is not enough data.
+func VolumeSpike(klines []exchange.Kline, minutes int) float64 {
+	if minutes < 1 || len(klines) <= minutes {
+		return 0
+	}
+
+	split := len(klines) - minutes
+	baseline := averageVolume(klines[:split])
+	if baseline == 0 {
+		return 0
+	}
+	return averageVolume(klines[split:]) / baseline
+}
+
+func averageVolume(klines []exchange.Kline) float64 {
+	total := 0.0
+	for _, k := range klines {
+		total += k.Volume
+	}
+	return total / float64(len(klines))
+}
+
+// OpenInterestChange returns the change in percent between the first and the last open interest.
+func OpenInterestChange(history []exchange.OpenInterest) float64 {
+	if len(history) < 2 || history[0].Value == 0 {
+		return 0
+	}
+	first := history[0].Value
+	return (history[len(history)-1].Value - first) * 100 / first
+}
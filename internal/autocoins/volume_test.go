@@ -0,0 +1,34 @@
+package autocoins
+
+import (
+	"testing"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func TestVolumeSpike(t *testing.T) {
+	klines := make([]exchange.Kline, 60)
+	for i := range klines {
+		klines[i].Volume = 10
+		if i >= 45 {
+			klines[i].Volume = 40
+		}
+	}
+
+	if spike := VolumeSpike(klines, 15); spike != 4 {
+		t.Errorf("invalid volume spike: expected %v got %v", 4.0, spike)
+	}
+	if spike := VolumeSpike(klines[:10], 15); spike != 0 {
+		t.Errorf("invalid volume spike without enough data: expected %v got %v", 0.0, spike)
+	}
+}
+
+func TestOpenInterestChange(t *testing.T) {
+	history := []exchange.OpenInterest{{Value: 200}, {Value: 250}, {Value: 150}}
+	if change := OpenInterestChange(history); change != -25 {
+		t.Errorf("invalid open interest change: expected %v got %v", -25.0, change)
+	}
+	if change := OpenInterestChange(history[:1]); change != 0 {
+		t.Errorf("invalid open interest change without enough data: expected %v got %v", 0.0, change)
+	}
+}
@@ -4,17 +4,21 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"time"
 
+	"github.com/LompeBoer/go-autocoins/internal/logger"
 	"github.com/LompeBoer/go-autocoins/internal/pairslist"
 )
 
 // RunLoop the main loop that will be run by `Run`
 func (a *AutoCoins) RunLoop() {
-	log.Println("Calculating coin list ...")
+	logger.Infof("Calculating coin list ...")
 	startTime := time.Now()
+	a.applyOverrides()
+	if schedule := a.ApplySchedule(startTime); schedule != "" {
+		logger.Infof("Using schedule '%s'\n", schedule)
+	}
 
 	// Download the permitted and safe pairs list from Google Docs.
 	var pairsList []pairslist.Pair
@@ -31,82 +35,121 @@ func (a *AutoCoins) RunLoop() {
 	if err != nil {
 		a.OutputWriter.WriteError(err.Error())
 	} else if a.DisableWrite {
-		log.Println("READ ONLY not updating WickHunter")
+		logger.Infof("READ ONLY not updating WickHunter")
 	} else if len(lists.Permitted) == 0 {
 		a.OutputWriter.WriteError("ERROR: No permitted coins (no action performed)")
+	} else if backupErr := a.BackupDatabase(); backupErr != nil {
+		a.OutputWriter.WriteError(fmt.Sprintf("ERROR: Unable to backup the WickHunter database (no action performed): %s", backupErr.Error()))
 	} else {
-		a.BackupDatabase()
-		a.BotAPI.UpdatePermittedList(lists.Permitted, lists.NotTrading)
+		if err := a.BotAPI.UpdatePermittedList(lists.Permitted, lists.NotTrading); err != nil {
+			a.OutputWriter.WriteError(fmt.Sprintf("ERROR: Unable to update WickHunter: %s", err.Error()))
+			if a.Metrics != nil {
+				a.Metrics.observeBotAPIError(err)
+			}
+		}
 	}
 
-	a.outputRun(objects, lists, startTime)
+	a.outputRun(objects, lists, startTime, err)
+	a.setLastRun(objects, lists, startTime, err)
+	if a.Metrics != nil {
+		a.Metrics.observeRun(objects, lists, time.Since(startTime), err, a.ExchangeAPI.Weight())
+	}
 }
 
-func (a *AutoCoins) outputRun(objects []SymbolDataObject, lists SymbolLists, startTime time.Time) {
+func (a *AutoCoins) outputRun(objects []SymbolDataObject, lists SymbolLists, startTime time.Time, runErr error) {
 	if len(objects) > 0 {
-		a.OutputWriter.WriteResult(objects, lists)
+		a.OutputWriter.WriteResult(&RunResult{
+			Time:     startTime,
+			Duration: time.Since(startTime),
+			Schedule: a.currentSchedule(),
+			Objects:  objects,
+			Lists:    lists,
+		})
 
 		p := len(lists.Permitted)
 		q := len(lists.Quarantined)
-		log.Printf("Permitted: %d Quarantined: %d Total: %d\n", p, q, p+q)
+		logger.Infof("Permitted: %d Quarantined: %d Total: %d\n", p, q, p+q)
 	}
 
-	log.Printf("Elapsed: %s\n", time.Since(startTime))
-	log.Printf("API Weight used: %d/%d\n", a.ExchangeAPI.UsedWeight, a.ExchangeAPI.WeightLimit)
+	logger.Infof("Elapsed: %s\n", time.Since(startTime))
+	weight := a.ExchangeAPI.Weight()
+	logger.Infof("API Weight used: %d/%d\n", weight.Used, weight.Limit)
+
+	if len(objects) > 0 || runErr != nil {
+		a.recordRun(objects, lists, startTime, weight, runErr)
+	}
 }
 
 // Start running the loop with a wait interval defined in settings.
 func (a *AutoCoins) Run() {
 	ctx, cancel := context.WithCancel(context.Background())
+	a.mutex.Lock()
 	a.ctx = ctx
 	a.cancel = cancel
+	a.running = true
+	a.mutex.Unlock()
 	a.wg.Add(1)
 	defer a.wg.Done()
 
-	a.IsRunning = true
+	trigger := a.triggerChannel()
+	triggered := false
 	for {
-		a.RunLoop()
+		if triggered || !a.IsPaused() {
+			a.RunLoop()
+		} else {
+			logger.Infof("Paused, skipping run")
+		}
 
+		triggered = false
 		select {
 		case <-ctx.Done():
 			return
+		case <-trigger:
+			triggered = true
 		case <-time.After(time.Duration(a.Settings.Refresh) * time.Minute):
-			a.ReloadConfig()
 		}
+		a.ReloadConfig()
 	}
 }
 
 // Stop the run loop.
 func (a *AutoCoins) Stop() {
-	if !a.IsRunning {
+	a.mutex.Lock()
+	if !a.running {
+		a.mutex.Unlock()
 		return
 	}
-	a.IsRunning = false
+	a.running = false
+	cancel := a.cancel
+	a.mutex.Unlock()
+
 	a.ExchangeAPI.Cancel()
-	a.cancel()
+	cancel()
 	a.wg.Wait()
 }
 
 // Reload the settings (from disk.)
 func (a *AutoCoins) ReloadConfig() {
-	a.Settings = *a.Settings.ReloadConfig()
+	settings := a.Settings.ReloadConfig()
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.Settings = *settings
 }
 
-func (a *AutoCoins) BackupDatabase() {
+// BackupDatabase copies the WickHunter storage file to a .bak file.
+func (a *AutoCoins) BackupDatabase() error {
 	original, err := os.Open(a.StorageFilename)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer original.Close()
 
 	new, err := os.Create(a.StorageFilename + ".bak")
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer new.Close()
 
 	_, err = io.Copy(new, original)
-	if err != nil {
-		log.Fatal(err)
-	}
+	return err
 }
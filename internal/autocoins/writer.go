@@ -2,31 +2,44 @@ package autocoins
 
 import (
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
 	"github.com/LompeBoer/go-autocoins/internal/discord"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
 )
 
+// Writer outputs the result of every run and the errors.
 type Writer interface {
-	WriteResult([]MarketSwing, *QuarantineMessages) error
+	WriteResult(*RunResult) error
 	WriteError(string) error
 }
 
+// RunResult the result of a run that is passed to the writers.
+type RunResult struct {
+	Time         time.Time
+	Duration     time.Duration
+	Schedule     string // Schedule the name of the schedule used by the run.
+	Objects      []SymbolDataObject
+	Lists        SymbolLists
+	MarketSwings []MarketSwing
+	Messages     *QuarantineMessages
+}
+
 type OutputWriter struct {
 	Writers []Writer
 }
 
 // WriteResult outputs the calculated results from AutoCoins.
-func (w *OutputWriter) WriteResult(data []SymbolDataObject, lists SymbolLists) error {
-	marketSwings := CalculateMarketSwing(data)
-	q := w.writeQuarantineMessage(lists)
+// The market swings and the messages are calculated from the symbols.
+func (w *OutputWriter) WriteResult(result *RunResult) error {
+	result.MarketSwings = CalculateMarketSwing(result.Objects)
+	result.Messages = w.writeQuarantineMessage(result.Objects, result.Lists)
 
 	for _, wr := range w.Writers {
-		err := wr.WriteResult(marketSwings, q)
+		err := wr.WriteResult(result)
 		if err != nil {
-			log.Printf("error writing results: %s\n", err.Error())
+			logger.Errorf("unable to write results: %s\n", err.Error())
 		}
 	}
 	return nil
@@ -37,7 +50,7 @@ func (w *OutputWriter) WriteError(message string) error {
 	for _, wr := range w.Writers {
 		err := wr.WriteError(message)
 		if err != nil {
-			log.Printf("error writing error: %s\n", err.Error())
+			logger.Errorf("unable to write error: %s\n", err.Error())
 		}
 	}
 	return nil
@@ -52,13 +65,33 @@ type QuarantineMessages struct {
 	Failed         string
 }
 
-func (w *OutputWriter) writeQuarantineMessage(lists SymbolLists) *QuarantineMessages {
+// writeQuarantineMessage joins the lists, quarantined symbols are followed by the rules that quarantined them.
+func (w *OutputWriter) writeQuarantineMessage(data []SymbolDataObject, lists SymbolLists) *QuarantineMessages {
+	failedRules := map[string][]RuleResult{}
+	held := map[string]bool{}
+	for _, object := range data {
+		failedRules[object.Symbol.Name] = object.FailedRules()
+		held[object.Symbol.Name] = object.Held
+	}
+	withRules := func(symbols []string) string {
+		values := make([]string, 0, len(symbols))
+		for _, symbol := range symbols {
+			if rules := failedRules[symbol]; len(rules) > 0 {
+				symbol = fmt.Sprintf("%s (%s)", symbol, RuleNames(rules))
+			} else if held[symbol] {
+				symbol = fmt.Sprintf("%s (held)", symbol)
+			}
+			values = append(values, symbol)
+		}
+		return strings.Join(values, ", ")
+	}
+
 	return &QuarantineMessages{
-		NewQuarantined: strings.Join(lists.QuarantinedNew, ", "),
-		Quarantined:    strings.Join(lists.Quarantined, ", "),
+		NewQuarantined: withRules(lists.QuarantinedNew),
+		Quarantined:    withRules(lists.Quarantined),
 		Unquarantined:  strings.Join(lists.QuarantinedRemoved, ", "),
-		OpenPositions:  strings.Join(lists.QuarantinedSkipped, ", "),
-		Excluded:       strings.Join(lists.QuarantinedExcluded, ", "),
+		OpenPositions:  withRules(lists.QuarantinedSkipped),
+		Excluded:       withRules(lists.QuarantinedExcluded),
 		Failed:         strings.Join(lists.FailedToProcess, ", "),
 	}
 }
@@ -67,7 +100,8 @@ func (w *OutputWriter) writeQuarantineMessage(lists SymbolLists) *QuarantineMess
 type ConsoleOutputWriter struct {
 }
 
-func (w *ConsoleOutputWriter) WriteResult(marketSwings []MarketSwing, q *QuarantineMessages) error {
+func (w *ConsoleOutputWriter) WriteResult(result *RunResult) error {
+	marketSwings, q := result.MarketSwings, result.Messages
 	b := strings.Builder{}
 	d := strings.Builder{}
 	for _, m := range marketSwings {
@@ -107,7 +141,7 @@ func (w *ConsoleOutputWriter) WriteResult(marketSwings []MarketSwing, q *Quarant
 }
 
 func (w *ConsoleOutputWriter) WriteError(message string) error {
-	log.Println(message)
+	logger.Errorf("%s\n", strings.TrimPrefix(message, "ERROR: "))
 	return nil
 }
 
@@ -115,10 +149,12 @@ func (w *ConsoleOutputWriter) WriteError(message string) error {
 type DiscordOutputWriter struct {
 	Version        string
 	MentionOnError bool                   // TODO: Update when changing config file.
+	Details        bool                   // Details adds an embed with the rules that quarantined the new coins.
 	WebHook        discord.DiscordWebHook // TODO: Update when changing config file.
 }
 
-func (w *DiscordOutputWriter) WriteResult(marketSwings []MarketSwing, q *QuarantineMessages) error {
+func (w *DiscordOutputWriter) WriteResult(result *RunResult) error {
+	marketSwings, q := result.MarketSwings, result.Messages
 	header := discord.DiscordEmbed{
 		Title:       "AutoCoins MarketSwing report",
 		Description: fmt.Sprintf("Generated %s (using v%s)", time.Now().Format("2006-01-02 15:04"), w.Version),
@@ -155,41 +191,59 @@ func (w *DiscordOutputWriter) WriteResult(marketSwings []MarketSwing, q *Quarant
 		quarantinedValue = "-"
 	}
 
+	// Long lists are split over multiple fields, embeds and messages by the webhook.
 	coins := discord.DiscordEmbed{}
 	if len(q.NewQuarantined) > 0 {
-		coins.Fields = append(coins.Fields, discord.DiscordEmbedField{
-			Name: "New quarantined", Value: q.NewQuarantined, Inline: false,
-		})
+		coins.Fields = append(coins.Fields, discord.SplitField("New quarantined", q.NewQuarantined, ", ")...)
 	}
-	coins.Fields = append(coins.Fields, discord.DiscordEmbedField{
-		Name: "Quarantined", Value: quarantinedValue, Inline: false,
-	})
+	coins.Fields = append(coins.Fields, discord.SplitField("Quarantined", quarantinedValue, ", ")...)
 	if len(q.Unquarantined) > 0 {
-		coins.Fields = append(coins.Fields, discord.DiscordEmbedField{
-			Name: "Unquarantined", Value: q.Unquarantined, Inline: false,
-		})
+		coins.Fields = append(coins.Fields, discord.SplitField("Unquarantined", q.Unquarantined, ", ")...)
 	}
 	if len(q.OpenPositions) > 0 {
-		coins.Fields = append(coins.Fields, discord.DiscordEmbedField{
-			Name: "Open positions - not quarantined", Value: q.OpenPositions, Inline: false,
-		})
+		coins.Fields = append(coins.Fields, discord.SplitField("Open positions - not quarantined", q.OpenPositions, ", ")...)
 	}
 	if len(q.Excluded) > 0 {
-		coins.Fields = append(coins.Fields, discord.DiscordEmbedField{
-			Name: "Excluded - not quarantined", Value: q.Excluded, Inline: false,
-		})
+		coins.Fields = append(coins.Fields, discord.SplitField("Excluded - not quarantined", q.Excluded, ", ")...)
 	}
 	if len(q.Failed) > 0 {
-		coins.Fields = append(coins.Fields, discord.DiscordEmbedField{
-			Name: "Failed to process", Value: q.Failed, Inline: false,
-		})
+		coins.Fields = append(coins.Fields, discord.SplitField("Failed to process", q.Failed, ", ")...)
 	}
 
 	msg.Embeds = append(msg.Embeds, coins)
+	if w.Details && len(result.Lists.QuarantinedNew) > 0 {
+		msg.Embeds = append(msg.Embeds, w.detailsEmbed(result))
+	}
 
 	return w.WebHook.SendMessage(msg)
 }
 
+// detailsEmbed lists every newly quarantined coin with the rules and values that quarantined it.
+func (w *DiscordOutputWriter) detailsEmbed(result *RunResult) discord.DiscordEmbed {
+	objects := map[string]*SymbolDataObject{}
+	for i := range result.Objects {
+		objects[result.Objects[i].Symbol.Name] = &result.Objects[i]
+	}
+
+	embed := discord.DiscordEmbed{Title: "New quarantined - details", Color: 15158332}
+	for _, symbol := range result.Lists.QuarantinedNew {
+		reasons := []string{}
+		if object, ok := objects[symbol]; ok {
+			for _, r := range object.FailedRules() {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", r.Rule, r.Reason))
+			}
+			if len(reasons) == 0 && object.Held {
+				reasons = append(reasons, "held")
+			}
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "-")
+		}
+		embed.Fields = append(embed.Fields, discord.SplitField(symbol, strings.Join(reasons, "\n"), "\n")...)
+	}
+	return embed
+}
+
 func (w *DiscordOutputWriter) WriteError(message string) error {
 	return w.WebHook.SendError(message, w.MentionOnError)
 }
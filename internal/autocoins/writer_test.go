@@ -0,0 +1,68 @@
+package autocoins
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/LompeBoer/go-autocoins/internal/discord"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func TestDiscordOutputWriterSplit(t *testing.T) {
+	bodies := []string{}
+	server := newRecordingServer(&bodies, nil)
+	defer server.Close()
+
+	result := &RunResult{Lists: SymbolLists{}}
+	for i := 0; i < 300; i++ {
+		symbol := fmt.Sprintf("COIN%03dUSDT", i)
+		result.Objects = append(result.Objects, SymbolDataObject{
+			Symbol:  exchange.Symbol{Name: symbol},
+			Results: []RuleResult{{Rule: "1hr", Passed: false, Reason: "1hr change 12.00% exceeds 10.00%"}},
+		})
+		result.Lists.Quarantined = append(result.Lists.Quarantined, symbol)
+		result.Lists.QuarantinedNew = append(result.Lists.QuarantinedNew, symbol)
+	}
+	result.Messages = (&OutputWriter{}).writeQuarantineMessage(result.Objects, result.Lists)
+	w := &DiscordOutputWriter{
+		WebHook: discord.DiscordWebHook{Enabled: true, URL: server.URL},
+		Details: true,
+	}
+	if err := w.WriteResult(result); err != nil {
+		t.Fatalf("WriteResult returned error: %s", err.Error())
+	}
+
+	if len(bodies) < 2 {
+		t.Fatalf("invalid number of messages: expected more than 1 got %d", len(bodies))
+	}
+	details := 0
+	for _, body := range bodies {
+		var msg discord.DiscordWebhookMessage
+		if err := json.Unmarshal([]byte(body), &msg); err != nil {
+			t.Fatalf("invalid message: %s", err.Error())
+		}
+		length := 0
+		for _, e := range msg.Embeds {
+			length += e.Length()
+			for _, f := range e.Fields {
+				if len(f.Value) > discord.MaxFieldValueLength {
+					t.Errorf("field %s too long: got %d characters", f.Name, len(f.Value))
+				}
+				if strings.HasPrefix(f.Name, "COIN") {
+					details++
+				}
+			}
+		}
+		if length > discord.MaxMessageLength || len(msg.Embeds) > discord.MaxEmbedsPerMessage {
+			t.Errorf("message exceeds the limits: %d characters, %d embeds", length, len(msg.Embeds))
+		}
+	}
+	if details != 300 {
+		t.Errorf("invalid number of detail fields: expected %d got %d", 300, details)
+	}
+	if !strings.Contains(bodies[len(bodies)-1], "1hr: 1hr change 12.00% exceeds 10.00%") {
+		t.Errorf("the details do not contain the reason")
+	}
+}
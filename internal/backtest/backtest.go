@@ -0,0 +1,261 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
+)
+
+// UnsupportedRules the rules that need data that is not in the klines, they are removed from the backtest.
+var UnsupportedRules = []string{"tradeCount", "funding", "openInterest"}
+
+// Options the parameters of a backtest.
+type Options struct {
+	From             time.Time     // From first step, zero starts 24 hours after the first kline.
+	To               time.Time     // To last step, zero ends at the last kline.
+	Step             time.Duration // Step time between the runs, the refresh interval of the settings.
+	LargeMovePercent float64       // LargeMovePercent a price move of at least this percent is a large move.
+	LookaheadHours   int           // LookaheadHours the number of hours after a step that are checked for a large move.
+}
+
+// Step the decisions of a single run.
+type Step struct {
+	Time        time.Time         `json:"time"`
+	Permitted   []string          `json:"permitted"`
+	Quarantined []string          `json:"quarantined"`
+	Failed      []string          `json:"failed"`
+	Schedule    string            `json:"schedule,omitempty"`
+	Reasons     map[string]string `json:"reasons"` // Reasons the failed rules of the quarantined symbols.
+}
+
+// SymbolStats the summary of a symbol.
+type SymbolStats struct {
+	Symbol                string  `json:"symbol"`
+	Steps                 int     `json:"steps"`
+	QuarantinedSteps      int     `json:"quarantinedSteps"`
+	QuarantinedPercent    float64 `json:"quarantinedPercent"`
+	Flips                 int     `json:"flips"`                 // Flips the number of times the symbol changed between permitted and quarantined.
+	LargeMoves            int     `json:"largeMoves"`            // LargeMoves the number of distinct large moves.
+	LargeMovesQuarantined int     `json:"largeMovesQuarantined"` // LargeMovesQuarantined the number of large moves the symbol was quarantined at the last step before the move.
+}
+
+// largeMove a large move that is found by a step and counted once the steps pass it.
+type largeMove struct {
+	at          time.Time // at the open time of the first kline that reached the move percent.
+	quarantined bool      // quarantined the decision of the last step before the move.
+}
+
+// Summary the summary of all symbols.
+type Summary struct {
+	Steps                        int     `json:"steps"`
+	QuarantinedPercent           float64 `json:"quarantinedPercent"`
+	QuarantinedHours             float64 `json:"quarantinedHours"` // QuarantinedHours the tradeable hours given up, summed over all symbols.
+	Flips                        int     `json:"flips"`
+	LargeMoves                   int     `json:"largeMoves"`
+	LargeMovesQuarantined        int     `json:"largeMovesQuarantined"`
+	LargeMovesQuarantinedPercent float64 `json:"largeMovesQuarantinedPercent"`
+}
+
+// Result the timeline and the statistics of a backtest.
+type Result struct {
+	Steps   []Step        `json:"steps"`
+	Symbols []SymbolStats `json:"symbols"`
+	Summary Summary       `json:"summary"`
+}
+
+// Run steps through time and runs the quarantine rules of AutoCoins at every step.
+// The exchange and the clock of AutoCoins are replaced by the backtest exchange.
+func Run(a *autocoins.AutoCoins, e *Exchange, options Options) (*Result, error) {
+	if options.Step <= 0 {
+		return nil, fmt.Errorf("invalid step %s", options.Step)
+	}
+	first, last := e.Range()
+	if options.From.IsZero() {
+		options.From = first.Add(24 * time.Hour)
+	}
+	if options.To.IsZero() {
+		options.To = last
+	}
+	if !options.From.Before(options.To) {
+		return nil, fmt.Errorf("not enough klines to backtest from %s to %s", options.From, options.To)
+	}
+
+	a.ExchangeAPI = e
+	a.DerivativesAPI = nil
+	a.Clock = func() time.Time { return e.Time }
+	a.Settings.Rules = supportedRules(a.Settings.Rules)
+
+	result := &Result{}
+	stats := map[string]*SymbolStats{}
+	previous := map[string]bool{}
+	moves := map[string]*largeMove{}
+	lookahead := time.Duration(options.LookaheadHours) * time.Hour
+	for t := options.From; !t.After(options.To); t = t.Add(options.Step) {
+		e.Time = t
+		step, err := runStep(a, e, previous)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", t.Format(time.RFC3339), err.Error())
+		}
+		result.Steps = append(result.Steps, step)
+
+		quarantined := map[string]bool{}
+		for _, s := range step.Quarantined {
+			quarantined[s] = true
+		}
+		for _, name := range append(append([]string{}, step.Permitted...), step.Quarantined...) {
+			s, ok := stats[name]
+			if !ok {
+				s = &SymbolStats{Symbol: name}
+				stats[name] = s
+			}
+			s.Steps++
+			if quarantined[name] {
+				s.QuarantinedSteps++
+			}
+			if wasQuarantined, ok := previous[name]; ok && wasQuarantined != quarantined[name] {
+				s.Flips++
+			}
+			previous[name] = quarantined[name]
+
+			// The steps ahead of a move find the same move, it is counted once using the last decision before it.
+			if move, ok := moves[name]; ok {
+				if !t.After(move.at) {
+					move.quarantined = quarantined[name]
+					continue
+				}
+				s.countMove(move)
+				delete(moves, name)
+			}
+			if at, ok := e.largeMove(name, t, lookahead, options.LargeMovePercent); ok {
+				moves[name] = &largeMove{at: at, quarantined: quarantined[name]}
+			}
+		}
+	}
+	for name, move := range moves {
+		stats[name].countMove(move)
+	}
+
+	for _, s := range stats {
+		s.QuarantinedPercent = float64(s.QuarantinedSteps) * 100 / float64(s.Steps)
+		result.Symbols = append(result.Symbols, *s)
+	}
+	sort.Slice(result.Symbols, func(i, j int) bool { return result.Symbols[i].Symbol < result.Symbols[j].Symbol })
+	result.Summary = summarize(result, options.Step)
+	return result, nil
+}
+
+// runStep runs the rules at the current time of the exchange using the schedule active at that time.
+// The positions are the result of the previous step, there are no open positions.
+func runStep(a *autocoins.AutoCoins, e *Exchange, previous map[string]bool) (Step, error) {
+	settings := a.Settings
+	defer func() { a.Settings = settings }()
+	schedule := a.ApplySchedule(e.Time)
+
+	symbols, err := e.GetSymbols()
+	if err != nil {
+		return Step{}, err
+	}
+	sort.Sort(exchange.BySymbolName(symbols))
+
+	objects, err := a.CalculateSymbols(symbols)
+	if err != nil {
+		return Step{}, err
+	}
+
+	positions := make([]wickhunter.Position, 0, len(symbols))
+	for _, s := range symbols {
+		positions = append(positions, wickhunter.Position{
+			Symbol:    s.Name,
+			Permitted: !previous[s.Name],
+			State:     "Neutral",
+		})
+	}
+	lists, err := a.MakeLists(objects, positions)
+	if err != nil {
+		return Step{}, err
+	}
+
+	step := Step{
+		Time:        e.Time,
+		Permitted:   lists.Permitted,
+		Quarantined: append(append(append([]string{}, lists.Quarantined...), lists.QuarantinedSkipped...), lists.QuarantinedExcluded...),
+		Failed:      lists.FailedToProcess,
+		Schedule:    schedule,
+		Reasons:     map[string]string{},
+	}
+	for _, object := range objects {
+		if object.ShouldQuarantine() && !object.APIFailed {
+			reason := autocoins.RuleNames(object.FailedRules())
+			if object.Held && reason == "" {
+				reason = "held"
+			}
+			step.Reasons[object.Symbol.Name] = reason
+		}
+	}
+	return step, nil
+}
+
+func (s *SymbolStats) countMove(move *largeMove) {
+	s.LargeMoves++
+	if move.quarantined {
+		s.LargeMovesQuarantined++
+	}
+}
+
+// largeMove returns the open time of the first kline within the duration that moved at least `percent` from the close at `from`.
+// Returns false when there is no such move or the klines do not cover the whole duration.
+func (e *Exchange) largeMove(symbol string, from time.Time, duration time.Duration, percent float64) (time.Time, bool) {
+	past := e.closed(symbol, time.Time{}, from)
+	future := e.closed(symbol, from, from.Add(duration))
+	if len(past) == 0 || len(future) == 0 || future[len(future)-1].OpenTime.Add(time.Minute).Before(from.Add(duration)) {
+		return time.Time{}, false
+	}
+	price := past[len(past)-1].Close
+	if price == 0 {
+		return time.Time{}, false
+	}
+	for _, k := range future {
+		if math.Max(math.Abs(k.High-price), math.Abs(k.Low-price))*100/price >= percent {
+			return k.OpenTime, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func summarize(result *Result, step time.Duration) Summary {
+	summary := Summary{Steps: len(result.Steps)}
+	steps, quarantined := 0, 0
+	for _, s := range result.Symbols {
+		steps += s.Steps
+		quarantined += s.QuarantinedSteps
+		summary.Flips += s.Flips
+		summary.LargeMoves += s.LargeMoves
+		summary.LargeMovesQuarantined += s.LargeMovesQuarantined
+	}
+	summary.QuarantinedHours = float64(quarantined) * step.Hours()
+	if steps > 0 {
+		summary.QuarantinedPercent = float64(quarantined) * 100 / float64(steps)
+	}
+	if summary.LargeMoves > 0 {
+		summary.LargeMovesQuarantinedPercent = float64(summary.LargeMovesQuarantined) * 100 / float64(summary.LargeMoves)
+	}
+	return summary
+}
+
+func supportedRules(rules []string) []string {
+	supported := []string{}
+	for _, rule := range rules {
+		if autocoins.ContainsString(UnsupportedRules, rule) {
+			logger.Infof("The %s rule is not available in a backtest and is skipped\n", rule)
+			continue
+		}
+		supported = append(supported, rule)
+	}
+	return supported
+}
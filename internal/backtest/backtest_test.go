@@ -0,0 +1,106 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+var start = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestExchange returns an exchange with a flat symbol and a symbol that rises 15% in the 37th hour.
+func newTestExchange() *Exchange {
+	flat := []exchange.Kline{}
+	pump := []exchange.Kline{}
+	for i := 0; i < 48*60; i++ {
+		openTime := start.Add(time.Duration(i) * time.Minute)
+		flat = append(flat, exchange.Kline{OpenTime: openTime, Open: 100, High: 100, Low: 100, Close: 100})
+
+		price := 100.0
+		if i >= 36*60 {
+			price = 100 + 15*float64(min(i-36*60, 60))/60
+		}
+		pump = append(pump, exchange.Kline{OpenTime: openTime, Open: price, High: price, Low: price, Close: price})
+	}
+
+	e := NewExchange()
+	e.AddSymbol(exchange.Symbol{Name: "FLATUSDT"}, flat, nil)
+	e.AddSymbol(exchange.Symbol{Name: "PUMPUSDT"}, pump, nil)
+	return e
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func TestExchangeNoFutureData(t *testing.T) {
+	e := newTestExchange()
+	e.Time = start.Add(10 * time.Hour)
+	symbol := exchange.Symbol{Name: "PUMPUSDT"}
+
+	klines, err := e.GetKline(symbol, exchange.OneMinute, 100)
+	if err != nil {
+		t.Fatalf("GetKline returned error: %s", err.Error())
+	}
+	if len(klines) != 100 || !klines[99].OpenTime.Equal(e.Time.Add(-time.Minute)) {
+		t.Errorf("invalid last kline: expected %s got %s", e.Time.Add(-time.Minute), klines[len(klines)-1].OpenTime)
+	}
+
+	e.Time = start.Add(36*time.Hour + 30*time.Minute)
+	months, err := e.GetKline(symbol, exchange.OneMonth, 2)
+	if err != nil {
+		t.Fatalf("GetKline returned error: %s", err.Error())
+	}
+	if len(months) != 1 || months[0].High >= 108 {
+		t.Errorf("invalid month kline: expected a high below the future price got %+v", months)
+	}
+}
+
+func TestRun(t *testing.T) {
+	a := &autocoins.AutoCoins{
+		Settings: autocoins.Settings{
+			AutoCoins: autocoins.SettingsAutoCoins{
+				Max1hrPercent:           5,
+				Max4hrPercent:           5,
+				Max24hrPercent:          100,
+				CooldownHours:           4,
+				VolatilityCooldownHours: 4,
+			},
+			Rules: []string{"1hr", "4hr", "tradeCount"},
+		},
+	}
+	result, err := Run(a, newTestExchange(), Options{
+		Step:             time.Hour,
+		LargeMovePercent: 10,
+		LookaheadHours:   4,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err.Error())
+	}
+
+	if len(result.Steps) != 24 {
+		t.Errorf("invalid step count: expected %d got %d", 24, len(result.Steps))
+	}
+	stats := map[string]SymbolStats{}
+	for _, s := range result.Symbols {
+		stats[s.Symbol] = s
+	}
+	if s := stats["FLATUSDT"]; s.QuarantinedSteps != 0 || s.LargeMoves != 0 {
+		t.Errorf("FLATUSDT: expected no quarantine and no large moves got %+v", s)
+	}
+	pump := stats["PUMPUSDT"]
+	if pump.QuarantinedSteps == 0 || pump.Flips != 2 {
+		t.Errorf("PUMPUSDT: expected quarantine with 2 flips got %+v", pump)
+	}
+	if pump.LargeMoves != 1 || pump.LargeMovesQuarantined != 0 {
+		t.Errorf("PUMPUSDT: expected one large move that was not quarantined ahead got %+v", pump)
+	}
+	if result.Summary.Flips != 2 {
+		t.Errorf("invalid summary flips: expected %d got %d", 2, result.Summary.Flips)
+	}
+}
@@ -0,0 +1,178 @@
+// Package backtest replays historical klines through the quarantine logic of AutoCoins.
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+// Exchange implements exchange.ExchangeService using historical klines.
+// Only the data that was available at the current Time is returned.
+type Exchange struct {
+	Time    time.Time
+	symbols []exchange.Symbol
+	klines  map[string]map[exchange.KlineInterval][]exchange.Kline
+}
+
+func NewExchange() *Exchange {
+	return &Exchange{
+		klines: map[string]map[exchange.KlineInterval][]exchange.Kline{},
+	}
+}
+
+// AddSymbol adds the symbol with its 1 minute and 1 month klines.
+// When the onboard date of the symbol is unknown the open time of the first kline is used.
+func (e *Exchange) AddSymbol(symbol exchange.Symbol, kline1Minute []exchange.Kline, kline1Month []exchange.Kline) {
+	sortKlines(kline1Minute)
+	sortKlines(kline1Month)
+	if symbol.OnboardDate.IsZero() || symbol.OnboardDate.Unix() == 0 {
+		switch {
+		case len(kline1Month) > 0:
+			symbol.OnboardDate = kline1Month[0].OpenTime
+		case len(kline1Minute) > 0:
+			symbol.OnboardDate = kline1Minute[0].OpenTime
+		}
+	}
+	e.symbols = append(e.symbols, symbol)
+	e.klines[symbol.Name] = map[exchange.KlineInterval][]exchange.Kline{
+		exchange.OneMinute: kline1Minute,
+		exchange.OneMonth:  kline1Month,
+	}
+}
+
+// Range returns the first and last open time of the 1 minute klines of all symbols.
+func (e *Exchange) Range() (time.Time, time.Time) {
+	var first, last time.Time
+	for _, intervals := range e.klines {
+		klines := intervals[exchange.OneMinute]
+		if len(klines) == 0 {
+			continue
+		}
+		if first.IsZero() || klines[0].OpenTime.Before(first) {
+			first = klines[0].OpenTime
+		}
+		if end := klines[len(klines)-1].OpenTime; end.After(last) {
+			last = end
+		}
+	}
+	return first, last
+}
+
+// GetSymbols returns the symbols that are listed at the current time.
+func (e *Exchange) GetSymbols() ([]exchange.Symbol, error) {
+	symbols := []exchange.Symbol{}
+	for _, s := range e.symbols {
+		if !s.OnboardDate.After(e.Time) {
+			symbols = append(symbols, s)
+		}
+	}
+	return symbols, nil
+}
+
+// GetTickers calculates the 24 hour tickers from the 1 minute klines.
+// The trade count is not available in the klines.
+func (e *Exchange) GetTickers() ([]exchange.Ticker, error) {
+	tickers := []exchange.Ticker{}
+	for _, s := range e.symbols {
+		klines := e.closed(s.Name, e.Time.Add(-24*time.Hour), e.Time)
+		if len(klines) == 0 {
+			continue
+		}
+		ticker := exchange.Ticker{
+			Symbol:    s.Name,
+			LastPrice: klines[len(klines)-1].Close,
+		}
+		if open := klines[0].Open; open != 0 {
+			ticker.PriceChangePercent24h = (ticker.LastPrice - open) * 100 / open
+		}
+		for _, k := range klines {
+			ticker.Volume += k.Volume
+			ticker.QuoteVolume += k.QuoteVolume
+		}
+		tickers = append(tickers, ticker)
+	}
+	return tickers, nil
+}
+
+// GetKline returns the last `limit` klines that closed before the current time.
+// The kline of the current month is built from the 1 minute klines so it does not contain future prices.
+func (e *Exchange) GetKline(symbol exchange.Symbol, interval exchange.KlineInterval, limit int) ([]exchange.Kline, error) {
+	intervals, ok := e.klines[symbol.Name]
+	if !ok {
+		return nil, fmt.Errorf("no klines for %s", symbol.Name)
+	}
+
+	var klines []exchange.Kline
+	switch interval {
+	case exchange.OneMinute:
+		klines = e.closed(symbol.Name, time.Time{}, e.Time)
+	case exchange.OneMonth:
+		now := e.Time.UTC()
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		months := intervals[exchange.OneMonth]
+		end := sort.Search(len(months), func(i int) bool { return !months[i].OpenTime.Before(monthStart) })
+		klines = append([]exchange.Kline{}, months[:end]...)
+		if current, ok := combine(e.closed(symbol.Name, monthStart, e.Time)); ok {
+			current.OpenTime = monthStart
+			klines = append(klines, current)
+		}
+	default:
+		return nil, fmt.Errorf("interval %s not available in backtest", interval)
+	}
+
+	if len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+	return klines, nil
+}
+
+func (e *Exchange) RateLimitChecks(symbolCount int) {}
+
+func (e *Exchange) Weight() exchange.Weight {
+	return exchange.Weight{}
+}
+
+func (e *Exchange) Cancel() {}
+
+// closed returns the 1 minute klines that opened at or after `from` and closed before `to`.
+func (e *Exchange) closed(symbol string, from time.Time, to time.Time) []exchange.Kline {
+	klines := e.klines[symbol][exchange.OneMinute]
+	start := sort.Search(len(klines), func(i int) bool { return !klines[i].OpenTime.Before(from) })
+	end := sort.Search(len(klines), func(i int) bool { return klines[i].OpenTime.Add(time.Minute).After(to) })
+	if end < start {
+		return nil
+	}
+	return klines[start:end]
+}
+
+// combine returns a single kline for all the klines.
+func combine(klines []exchange.Kline) (exchange.Kline, bool) {
+	if len(klines) == 0 {
+		return exchange.Kline{}, false
+	}
+	k := exchange.Kline{
+		OpenTime: klines[0].OpenTime,
+		Open:     klines[0].Open,
+		High:     klines[0].High,
+		Low:      klines[0].Low,
+		Close:    klines[len(klines)-1].Close,
+	}
+	for _, kline := range klines {
+		if kline.High > k.High {
+			k.High = kline.High
+		}
+		if kline.Low < k.Low {
+			k.Low = kline.Low
+		}
+		k.Volume += kline.Volume
+		k.QuoteVolume += kline.QuoteVolume
+	}
+	return k, true
+}
+
+func sortKlines(klines []exchange.Kline) {
+	sort.Slice(klines, func(i, j int) bool { return klines[i].OpenTime.Before(klines[j].OpenTime) })
+}
@@ -0,0 +1,145 @@
+package backtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/exchange/binance"
+)
+
+const (
+	ExchangeInfoFile  = "exchangeInfo.json" // ExchangeInfoFile (optional) Binance exchange information with the onboard dates.
+	Kline1MinuteFiles = "-1m*.json"         // Kline1MinuteFiles files with the 1 minute klines of a symbol: SYMBOL-1m.json, SYMBOL-1m-2.json, ...
+	Kline1MonthFiles  = "-month*.json"      // Kline1MonthFiles files with the 1 month klines of a symbol: SYMBOL-month.json.
+	MaxKlineLimit     = 1500                // MaxKlineLimit the maximum kline limit of the Binance API.
+)
+
+// LoadDirectory loads the klines from a cache directory with responses of the Binance kline endpoint.
+// The 1 minute klines of a symbol can be split over multiple files, duplicate klines are removed.
+func LoadDirectory(dir string) (*Exchange, error) {
+	symbols := map[string]exchange.Symbol{}
+	if data, err := os.ReadFile(filepath.Join(dir, ExchangeInfoFile)); err == nil {
+		list, err := binance.ParseExchangeInfo(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", ExchangeInfoFile, err.Error())
+		}
+		for _, s := range list {
+			symbols[s.Name] = s
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+Kline1MinuteFiles))
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for _, f := range files {
+		base := filepath.Base(f)
+		names[base[:strings.Index(base, "-1m")]] = true
+	}
+
+	e := NewExchange()
+	for name := range names {
+		kline1Minute, err := loadKlineFiles(filepath.Join(dir, name+Kline1MinuteFiles))
+		if err != nil {
+			return nil, err
+		}
+		kline1Month, err := loadKlineFiles(filepath.Join(dir, name+Kline1MonthFiles))
+		if err != nil {
+			return nil, err
+		}
+		symbol, ok := symbols[name]
+		if !ok {
+			symbol = exchange.Symbol{Name: name}
+		}
+		e.AddSymbol(symbol, kline1Minute, kline1Month)
+	}
+	if len(e.symbols) == 0 {
+		return nil, fmt.Errorf("no kline files found in '%s'", dir)
+	}
+	return e, nil
+}
+
+// LoadDebugResponses loads the klines from the responses saved with DebugSaveResponses.
+// The files are named after the hash of the request url, so every kline limit is tried for the symbols in the exchange information.
+func LoadDebugResponses(dir string, baseURL string) (*Exchange, error) {
+	data, err := os.ReadFile(debugFilename(dir, baseURL+"/fapi/v1/exchangeInfo"))
+	if err != nil {
+		return nil, fmt.Errorf("no saved exchange information: %s", err.Error())
+	}
+	symbols, err := binance.ParseExchangeInfo(data)
+	if err != nil {
+		return nil, err
+	}
+
+	e := NewExchange()
+	for _, symbol := range symbols {
+		kline1Minute, err := loadDebugKlines(dir, baseURL, symbol.Name, exchange.OneMinute)
+		if err != nil {
+			return nil, err
+		}
+		if len(kline1Minute) == 0 {
+			continue
+		}
+		kline1Month, err := loadDebugKlines(dir, baseURL, symbol.Name, exchange.OneMonth)
+		if err != nil {
+			return nil, err
+		}
+		e.AddSymbol(symbol, kline1Minute, kline1Month)
+	}
+	if len(e.symbols) == 0 {
+		return nil, fmt.Errorf("no saved klines found in '%s'", dir)
+	}
+	return e, nil
+}
+
+func loadDebugKlines(dir string, baseURL string, symbol string, interval exchange.KlineInterval) ([]exchange.Kline, error) {
+	files := []string{}
+	for limit := 1; limit <= MaxKlineLimit; limit++ {
+		url := fmt.Sprintf("%s/fapi/v1/klines?symbol=%s&interval=%s&limit=%d", baseURL, symbol, interval, limit)
+		filename := debugFilename(dir, url)
+		if _, err := os.Stat(filename); err == nil {
+			files = append(files, filename)
+		}
+	}
+	return loadKlines(files)
+}
+
+func debugFilename(dir string, url string) string {
+	return filepath.Join(dir, filepath.Base(binance.FilenameForURL(url)))
+}
+
+func loadKlineFiles(pattern string) ([]exchange.Kline, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return loadKlines(files)
+}
+
+// loadKlines loads the klines from the files, klines with the same open time are only added once.
+func loadKlines(files []string) ([]exchange.Kline, error) {
+	klines := []exchange.Kline{}
+	seen := map[int64]bool{}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		values, err := binance.ParseKlines(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", f, err.Error())
+		}
+		for _, k := range values {
+			if !seen[k.OpenTime.Unix()] {
+				seen[k.OpenTime.Unix()] = true
+				klines = append(klines, k)
+			}
+		}
+	}
+	sortKlines(klines)
+	return klines, nil
+}
@@ -0,0 +1,284 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+)
+
+// SweepParameters the names of the SettingsAutoCoins fields that can be swept (the names in the config file).
+var SweepParameters = []string{"max1hrPercent", "max4hrPercent", "max24hrPercent", "cooldownHrs", "minAthPercent", "minAge"}
+
+// DefaultObjectives scores for a careful, a balanced and an aggressive account.
+var DefaultObjectives = []Objective{
+	{Name: "safe", MoveWeight: 10, HourWeight: 0.05, FlipWeight: 0},
+	{Name: "balanced", MoveWeight: 5, HourWeight: 0.1, FlipWeight: 0.5},
+	{Name: "aggressive", MoveWeight: 2, HourWeight: 0.2, FlipWeight: 1},
+}
+
+// Parameter a SettingsAutoCoins field and the values to try.
+type Parameter struct {
+	Name   string
+	Values []float64
+}
+
+// Objective scores a backtest: the distinct large moves (wicks) quarantined ahead add to the score,
+// the quarantined hours (tradeable hours given up) and the flips subtract from it.
+type Objective struct {
+	Name       string  `json:"name"`
+	MoveWeight float64 `json:"moveWeight"`
+	HourWeight float64 `json:"hourWeight"`
+	FlipWeight float64 `json:"flipWeight"`
+}
+
+// Score returns the score of the summary.
+func (o Objective) Score(s Summary) float64 {
+	return o.MoveWeight*float64(s.LargeMovesQuarantined) - o.HourWeight*s.QuarantinedHours - o.FlipWeight*float64(s.Flips)
+}
+
+// SweepOptions the parameters of a sweep.
+type SweepOptions struct {
+	Options
+	Parameters []Parameter
+	Objectives []Objective // Objectives the results are sorted by the score of the first objective.
+	Random     int         // Random the number of random combinations to try, 0 tries all combinations.
+	Seed       int64       // Seed of the random combinations.
+}
+
+// SweepResult the summary and scores of one combination of settings.
+type SweepResult struct {
+	Settings map[string]float64 `json:"settings"`
+	Summary  Summary            `json:"summary"`
+	Scores   map[string]float64 `json:"scores"`
+}
+
+// Sweep runs a backtest for each combination of the parameter values on top of the given settings.
+func Sweep(settings autocoins.Settings, e *Exchange, options SweepOptions) ([]SweepResult, error) {
+	if len(options.Parameters) == 0 {
+		return nil, fmt.Errorf("no parameters to sweep")
+	}
+	if len(options.Objectives) == 0 {
+		options.Objectives = DefaultObjectives
+	}
+	settings.Rules = supportedRules(settings.Rules)
+
+	results := []SweepResult{}
+	for _, combination := range combinations(options.Parameters, options.Random, options.Seed) {
+		s := settings
+		for name, value := range combination {
+			if err := SetParameter(&s.AutoCoins, name, value); err != nil {
+				return nil, err
+			}
+		}
+
+		result, err := Run(&autocoins.AutoCoins{Settings: s}, e, options.Options)
+		if err != nil {
+			return nil, err
+		}
+		scores := map[string]float64{}
+		for _, o := range options.Objectives {
+			scores[o.Name] = o.Score(result.Summary)
+		}
+		results = append(results, SweepResult{
+			Settings: combination,
+			Summary:  result.Summary,
+			Scores:   scores,
+		})
+	}
+
+	first := options.Objectives[0].Name
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Scores[first] > results[j].Scores[first] })
+	return results, nil
+}
+
+// combinations returns all combinations of the parameter values or `random` random unique combinations.
+func combinations(parameters []Parameter, random int, seed int64) []map[string]float64 {
+	total := 1
+	for _, p := range parameters {
+		total *= len(p.Values)
+	}
+	combination := func(index int) map[string]float64 {
+		values := map[string]float64{}
+		for _, p := range parameters {
+			values[p.Name] = p.Values[index%len(p.Values)]
+			index /= len(p.Values)
+		}
+		return values
+	}
+
+	list := []map[string]float64{}
+	if random <= 0 || random >= total {
+		for i := 0; i < total; i++ {
+			list = append(list, combination(i))
+		}
+		return list
+	}
+	r := rand.New(rand.NewSource(seed))
+	for _, i := range r.Perm(total)[:random] {
+		list = append(list, combination(i))
+	}
+	return list
+}
+
+// SetParameter sets the SettingsAutoCoins field by the name in the config file.
+func SetParameter(s *autocoins.SettingsAutoCoins, name string, value float64) error {
+	switch name {
+	case "max1hrPercent":
+		s.Max1hrPercent = int(value)
+	case "max4hrPercent":
+		s.Max4hrPercent = int(value)
+	case "max24hrPercent":
+		s.Max24hrPercent = int(value)
+	case "cooldownHrs":
+		if value < 1 {
+			return fmt.Errorf("invalid cooldownHrs %v", value)
+		}
+		s.CooldownHours = int(value)
+	case "minAthPercent":
+		s.MinAthPercent = int(value)
+	case "minAge":
+		s.MinAge = int(value)
+	default:
+		return fmt.Errorf("unknown parameter '%s' (use %s)", name, strings.Join(SweepParameters, ", "))
+	}
+	return nil
+}
+
+// ParseParameter parses "name=1,2,3" or "name=min:max:step".
+// The parameters are whole numbers, a value with a fraction is an error.
+func ParseParameter(value string) (Parameter, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return Parameter{}, fmt.Errorf("invalid parameter '%s' (use name=1,2,3 or name=min:max:step)", value)
+	}
+	p := Parameter{Name: parts[0]}
+	if !autocoins.ContainsString(SweepParameters, p.Name) {
+		return Parameter{}, fmt.Errorf("unknown parameter '%s' (use %s)", p.Name, strings.Join(SweepParameters, ", "))
+	}
+
+	if r := strings.Split(parts[1], ":"); len(r) == 3 {
+		values, err := parseFloats(r)
+		if err != nil {
+			return Parameter{}, fmt.Errorf("parameter %s: %s", p.Name, err.Error())
+		}
+		if err := checkWholeNumbers(values); err != nil {
+			return Parameter{}, fmt.Errorf("parameter %s: %s", p.Name, err.Error())
+		}
+		min, max, step := values[0], values[1], values[2]
+		if step <= 0 || max < min {
+			return Parameter{}, fmt.Errorf("parameter %s: invalid range %s", p.Name, parts[1])
+		}
+		for v := min; v <= max+step/1000; v += step {
+			p.Values = append(p.Values, v)
+		}
+		return p, nil
+	}
+
+	values, err := parseFloats(strings.Split(parts[1], ","))
+	if err != nil {
+		return Parameter{}, fmt.Errorf("parameter %s: %s", p.Name, err.Error())
+	}
+	if err := checkWholeNumbers(values); err != nil {
+		return Parameter{}, fmt.Errorf("parameter %s: %s", p.Name, err.Error())
+	}
+	p.Values = values
+	return p, nil
+}
+
+// checkWholeNumbers returns an error for the first value with a fraction, the settings are integers.
+func checkWholeNumbers(values []float64) error {
+	for _, v := range values {
+		if v != math.Trunc(v) {
+			return fmt.Errorf("%v is not a whole number", v)
+		}
+	}
+	return nil
+}
+
+// ParseObjective parses "name:moveWeight:hourWeight:flipWeight".
+func ParseObjective(value string) (Objective, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 || parts[0] == "" {
+		return Objective{}, fmt.Errorf("invalid objective '%s' (use name:moveWeight:hourWeight:flipWeight)", value)
+	}
+	weights, err := parseFloats(parts[1:])
+	if err != nil {
+		return Objective{}, fmt.Errorf("objective %s: %s", parts[0], err.Error())
+	}
+	return Objective{Name: parts[0], MoveWeight: weights[0], HourWeight: weights[1], FlipWeight: weights[2]}, nil
+}
+
+func parseFloats(values []string) ([]float64, error) {
+	floats := make([]float64, len(values))
+	for i, v := range values {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, err
+		}
+		floats[i] = f
+	}
+	return floats, nil
+}
+
+// WriteSweepCSV writes one row per result with the parameter values, the summary and the scores.
+func WriteSweepCSV(w io.Writer, results []SweepResult, parameters []Parameter, objectives []Objective) error {
+	header := []string{}
+	for _, p := range parameters {
+		header = append(header, p.Name)
+	}
+	header = append(header, "steps", "quarantinedPercent", "quarantinedHours", "flips", "largeMoves", "largeMovesQuarantined", "largeMovesQuarantinedPercent")
+	for _, o := range objectives {
+		header = append(header, "score_"+o.Name)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, r := range results {
+		row := []string{}
+		for _, p := range parameters {
+			row = append(row, formatFloat(r.Settings[p.Name]))
+		}
+		s := r.Summary
+		row = append(row,
+			strconv.Itoa(s.Steps),
+			formatFloat(s.QuarantinedPercent),
+			formatFloat(s.QuarantinedHours),
+			strconv.Itoa(s.Flips),
+			strconv.Itoa(s.LargeMoves),
+			strconv.Itoa(s.LargeMovesQuarantined),
+			formatFloat(s.LargeMovesQuarantinedPercent),
+		)
+		for _, o := range objectives {
+			row = append(row, formatFloat(r.Scores[o.Name]))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteSweepJSON writes the objectives and results as JSON.
+func WriteSweepJSON(w io.Writer, results []SweepResult, objectives []Objective) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Objectives []Objective   `json:"objectives"`
+		Results    []SweepResult `json:"results"`
+	}{objectives, results})
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,154 @@
+package backtest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/autocoins"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func TestParseParameter(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []float64
+	}{
+		{"max1hrPercent=3,5,8", []float64{3, 5, 8}},
+		{"cooldownHrs=2:6:2", []float64{2, 4, 6}},
+		{"minAthPercent=1:3:1", []float64{1, 2, 3}},
+	}
+	for _, test := range tests {
+		p, err := ParseParameter(test.value)
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.value, err.Error())
+			continue
+		}
+		if len(p.Values) != len(test.expected) {
+			t.Errorf("%s: expected %v got %v", test.value, test.expected, p.Values)
+			continue
+		}
+		for i := range p.Values {
+			if p.Values[i] != test.expected[i] {
+				t.Errorf("%s: expected %v got %v", test.value, test.expected, p.Values)
+				break
+			}
+		}
+	}
+
+	for _, value := range []string{"refresh=1,2", "max1hrPercent", "max1hrPercent=5:1:1", "max1hrPercent=a", "max1hrPercent=1:3:0.5", "minAge=7.5,14"} {
+		if _, err := ParseParameter(value); err == nil {
+			t.Errorf("%s: expected an error", value)
+		}
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	parameters := []Parameter{
+		{Name: "max1hrPercent", Values: []float64{3, 5, 8}},
+		{Name: "max4hrPercent", Values: []float64{5, 10}},
+	}
+	if all := combinations(parameters, 0, 1); len(all) != 6 {
+		t.Errorf("invalid number of combinations: expected %d got %d", 6, len(all))
+	}
+
+	random := combinations(parameters, 4, 1)
+	if len(random) != 4 {
+		t.Errorf("invalid number of random combinations: expected %d got %d", 4, len(random))
+	}
+	seen := map[[2]float64]bool{}
+	for _, c := range random {
+		key := [2]float64{c["max1hrPercent"], c["max4hrPercent"]}
+		if seen[key] {
+			t.Errorf("duplicate random combination %v", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestSweep(t *testing.T) {
+	settings := autocoins.Settings{
+		AutoCoins: autocoins.SettingsAutoCoins{
+			Max4hrPercent:           100,
+			Max24hrPercent:          100,
+			CooldownHours:           4,
+			VolatilityCooldownHours: 4,
+		},
+		Rules: []string{"1hr", "4hr"},
+	}
+	parameters := []Parameter{{Name: "max1hrPercent", Values: []float64{5, 20}}}
+	objectives := []Objective{{Name: "hours", HourWeight: 1}}
+	results, err := Sweep(settings, newTestExchange(), SweepOptions{
+		Options: Options{
+			Step:             time.Hour,
+			LargeMovePercent: 10,
+			LookaheadHours:   4,
+		},
+		Parameters: parameters,
+		Objectives: objectives,
+	})
+	if err != nil {
+		t.Fatalf("Sweep returned error: %s", err.Error())
+	}
+	if len(results) != 2 {
+		t.Fatalf("invalid number of results: expected %d got %d", 2, len(results))
+	}
+	if results[0].Settings["max1hrPercent"] != 20 || results[0].Summary.QuarantinedHours != 0 {
+		t.Errorf("invalid best result: expected max1hrPercent 20 without quarantine got %+v", results[0])
+	}
+	if results[1].Summary.QuarantinedHours != 3 || results[1].Scores["hours"] != -3 {
+		t.Errorf("invalid second result: expected 3 quarantined hours got %+v", results[1])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSweepCSV(&buf, results, parameters, objectives); err != nil {
+		t.Fatalf("WriteSweepCSV returned error: %s", err.Error())
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "max1hrPercent,steps,") || !strings.HasSuffix(lines[0], ",score_hours") {
+		t.Errorf("invalid csv: got %q", buf.String())
+	}
+}
+
+func TestObjectiveScoresDistinctMoves(t *testing.T) {
+	// Rises 6% in the 31st hour and wicks 15% up in the 35th hour, the wick is seen by several steps.
+	klines := []exchange.Kline{}
+	for i := 0; i < 40*60; i++ {
+		price := 100.0
+		if i >= 30*60 {
+			price = 100 + 6*float64(min(i-30*60, 60))/60
+		}
+		k := exchange.Kline{OpenTime: start.Add(time.Duration(i) * time.Minute), Open: price, High: price, Low: price, Close: price}
+		if i == 34*60+30 {
+			k.High = price * 1.15
+		}
+		klines = append(klines, k)
+	}
+	e := NewExchange()
+	e.AddSymbol(exchange.Symbol{Name: "WICKUSDT"}, klines, nil)
+
+	a := &autocoins.AutoCoins{Settings: autocoins.Settings{
+		AutoCoins: autocoins.SettingsAutoCoins{
+			Max1hrPercent:           100,
+			Max4hrPercent:           5,
+			Max24hrPercent:          100,
+			CooldownHours:           4,
+			VolatilityCooldownHours: 4,
+		},
+		Rules: []string{"4hr"},
+	}}
+	result, err := Run(a, e, Options{Step: time.Hour, LargeMovePercent: 10, LookaheadHours: 4})
+	if err != nil {
+		t.Fatalf("Run returned error: %s", err.Error())
+	}
+
+	s := result.Summary
+	if s.LargeMoves != 1 || s.LargeMovesQuarantined != 1 {
+		t.Fatalf("expected one wick quarantined ahead got %d/%d", s.LargeMovesQuarantined, s.LargeMoves)
+	}
+	objective := Objective{Name: "moves", MoveWeight: 10, HourWeight: 1}
+	if score := objective.Score(s); score != 10-s.QuarantinedHours {
+		t.Errorf("invalid score: expected %v got %v", 10-s.QuarantinedHours, score)
+	}
+}
@@ -0,0 +1,29 @@
+package autocoinsdb
+
+import (
+	"database/sql"
+	"log"
+
+	_ "modernc.org/sqlite"
+)
+
+// Database stores the state of AutoCoins that has to survive a restart.
+type Database struct {
+	db *sql.DB
+}
+
+func New(file string) *Database {
+	db, err := sql.Open("sqlite", file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+
+	return &Database{
+		db: db,
+	}
+}
+
+func (d *Database) Close() error {
+	return d.db.Close()
+}
@@ -0,0 +1,243 @@
+package autocoinsdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// Run a recorded AutoCoins run.
+type Run struct {
+	ID          int64
+	Time        time.Time
+	Duration    time.Duration
+	WeightUsed  int
+	WeightLimit int
+	Lists       string // Lists the JSON encoded symbol lists.
+	Error       string // Error the reason the run failed, empty for a successful run.
+}
+
+// RunSymbol the decision for a symbol in a recorded run.
+type RunSymbol struct {
+	RunID       int64
+	Time        time.Time // Time the time of the run.
+	Symbol      string
+	Quarantined bool
+	APIFailed   bool
+	Held        bool
+	Open        bool   // Open has an open position, it is not quarantined.
+	Excluded    bool   // Excluded is on the exclude list, it is not quarantined.
+	Values      string // Values the JSON encoded calculated values.
+	Results     string // Results the JSON encoded rule results.
+}
+
+func (d *Database) CreateHistoryTables() error {
+	queries := []string{
+		"CREATE TABLE IF NOT EXISTS [Run] (ID INTEGER PRIMARY KEY AUTOINCREMENT,Time INTEGER NOT NULL,Duration INTEGER NOT NULL,WeightUsed INTEGER NOT NULL,WeightLimit INTEGER NOT NULL,Lists TEXT NOT NULL,Error TEXT NOT NULL DEFAULT '');",
+		"CREATE TABLE IF NOT EXISTS [RunSymbol] (RunID INTEGER NOT NULL,Time INTEGER NOT NULL,Symbol TEXT NOT NULL,Quarantined INTEGER NOT NULL,APIFailed INTEGER NOT NULL,Held INTEGER NOT NULL,Open INTEGER NOT NULL DEFAULT 0,Excluded INTEGER NOT NULL DEFAULT 0,[Values] TEXT NOT NULL,Results TEXT NOT NULL,PRIMARY KEY (RunID, Symbol));",
+		"CREATE INDEX IF NOT EXISTS RunSymbolSymbolTime ON RunSymbol (Symbol, Time);",
+	}
+	for _, query := range queries {
+		if _, err := d.db.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	// Columns added after the first version of the tables.
+	columns := []struct{ table, column, definition string }{
+		{"Run", "Error", "TEXT NOT NULL DEFAULT ''"},
+		{"RunSymbol", "Open", "INTEGER NOT NULL DEFAULT 0"},
+		{"RunSymbol", "Excluded", "INTEGER NOT NULL DEFAULT 0"},
+	}
+	for _, c := range columns {
+		if err := d.addColumn(c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumn adds the column to the table when it does not exist yet.
+func (d *Database) addColumn(table string, column string, definition string) error {
+	rows, err := d.db.Query(fmt.Sprintf("PRAGMA table_info([%s]);", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, columnType string
+		var defaultValue interface{}
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_, err = d.db.Exec(fmt.Sprintf("ALTER TABLE [%s] ADD COLUMN [%s] %s;", table, column, definition))
+	return err
+}
+
+// InsertRun inserts the run with the decisions of all the symbols and returns the id of the run.
+func (d *Database) InsertRun(run Run, symbols []RunSymbol) (int64, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec("INSERT INTO Run(Time, Duration, WeightUsed, WeightLimit, Lists, Error) values(?, ?, ?, ?, ?, ?)",
+		toMillis(run.Time), run.Duration.Milliseconds(), run.WeightUsed, run.WeightLimit, run.Lists, run.Error)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO RunSymbol(RunID, Time, Symbol, Quarantined, APIFailed, Held, Open, Excluded, [Values], Results) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defer stmt.Close()
+	for _, item := range symbols {
+		_, err = stmt.Exec(id, toMillis(run.Time), item.Symbol, item.Quarantined, item.APIFailed, item.Held, item.Open, item.Excluded, item.Values, item.Results)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	return id, tx.Commit()
+}
+
+// SelectRuns returns the runs between from and to, oldest first.
+func (d *Database) SelectRuns(from time.Time, to time.Time) ([]Run, error) {
+	rows, err := d.db.Query("SELECT ID, Time, Duration, WeightUsed, WeightLimit, Lists, Error FROM Run WHERE Time >= ? AND Time <= ? ORDER BY Time ASC", toMillis(from), toMillis(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Run
+	for rows.Next() {
+		var i Run
+		var runTime, duration int64
+		if err := rows.Scan(
+			&i.ID,
+			&runTime,
+			&duration,
+			&i.WeightUsed,
+			&i.WeightLimit,
+			&i.Lists,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		i.Time = fromMillis(runTime)
+		i.Duration = time.Duration(duration) * time.Millisecond
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// SelectSymbolAt returns the decision of the last run at or before the given time.
+// Returns sql.ErrNoRows when the symbol was not processed before that time.
+func (d *Database) SelectSymbolAt(symbol string, at time.Time) (RunSymbol, error) {
+	row := d.db.QueryRow("SELECT RunID, Time, Symbol, Quarantined, APIFailed, Held, Open, Excluded, [Values], Results FROM RunSymbol WHERE Symbol = ? AND Time <= ? ORDER BY Time DESC LIMIT 1", symbol, toMillis(at))
+	return scanRunSymbol(row)
+}
+
+// SelectSymbolHistory returns the decisions for the symbol between from and to, oldest first.
+func (d *Database) SelectSymbolHistory(symbol string, from time.Time, to time.Time) ([]RunSymbol, error) {
+	rows, err := d.db.Query("SELECT RunID, Time, Symbol, Quarantined, APIFailed, Held, Open, Excluded, [Values], Results FROM RunSymbol WHERE Symbol = ? AND Time >= ? AND Time <= ? ORDER BY Time ASC", symbol, toMillis(from), toMillis(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RunSymbol
+	for rows.Next() {
+		i, err := scanRunSymbol(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// CountQuarantined returns the number of runs the symbol was quarantined and the number of runs the symbol was processed between from and to.
+func (d *Database) CountQuarantined(symbol string, from time.Time, to time.Time) (int, int, error) {
+	row := d.db.QueryRow("SELECT COALESCE(SUM(Quarantined), 0), COUNT(*) FROM RunSymbol WHERE Symbol = ? AND APIFailed = 0 AND Time >= ? AND Time <= ?", symbol, toMillis(from), toMillis(to))
+	var quarantined, total int
+	if err := row.Scan(&quarantined, &total); err != nil {
+		return 0, 0, err
+	}
+	return quarantined, total, nil
+}
+
+// DeleteRunsBefore removes the runs that started before the given time.
+func (d *Database) DeleteRunsBefore(before time.Time) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM RunSymbol WHERE Time < ?", toMillis(before)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM Run WHERE Time < ?", toMillis(before)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRunSymbol(row scanner) (RunSymbol, error) {
+	var i RunSymbol
+	var runTime int64
+	err := row.Scan(
+		&i.RunID,
+		&runTime,
+		&i.Symbol,
+		&i.Quarantined,
+		&i.APIFailed,
+		&i.Held,
+		&i.Open,
+		&i.Excluded,
+		&i.Values,
+		&i.Results,
+	)
+	i.Time = fromMillis(runTime)
+	return i, err
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func fromMillis(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
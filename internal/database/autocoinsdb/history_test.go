@@ -0,0 +1,40 @@
+package autocoinsdb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCreateHistoryTablesAddsColumns(t *testing.T) {
+	d := New(filepath.Join(t.TempDir(), "autocoins.db"))
+	defer d.Close()
+
+	// The tables as created by the first version.
+	queries := []string{
+		"CREATE TABLE [Run] (ID INTEGER PRIMARY KEY AUTOINCREMENT,Time INTEGER NOT NULL,Duration INTEGER NOT NULL,WeightUsed INTEGER NOT NULL,WeightLimit INTEGER NOT NULL,Lists TEXT NOT NULL);",
+		"CREATE TABLE [RunSymbol] (RunID INTEGER NOT NULL,Time INTEGER NOT NULL,Symbol TEXT NOT NULL,Quarantined INTEGER NOT NULL,APIFailed INTEGER NOT NULL,Held INTEGER NOT NULL,[Values] TEXT NOT NULL,Results TEXT NOT NULL,PRIMARY KEY (RunID, Symbol));",
+	}
+	for _, query := range queries {
+		if _, err := d.db.Exec(query); err != nil {
+			t.Fatalf("unable to create table: %s", err.Error())
+		}
+	}
+	if err := d.CreateHistoryTables(); err != nil {
+		t.Fatalf("CreateHistoryTables returned error: %s", err.Error())
+	}
+
+	now := time.Now()
+	_, err := d.InsertRun(Run{Time: now, Lists: "{}", Error: "failed"}, []RunSymbol{{Symbol: "XYZUSDT", Open: true, Values: "{}", Results: "[]"}})
+	if err != nil {
+		t.Fatalf("InsertRun returned error: %s", err.Error())
+	}
+	item, err := d.SelectSymbolAt("XYZUSDT", now)
+	if err != nil || !item.Open || item.Excluded {
+		t.Errorf("invalid symbol: expected open got %+v %v", item, err)
+	}
+	runs, err := d.SelectRuns(now.Add(-time.Minute), now)
+	if err != nil || len(runs) != 1 || runs[0].Error != "failed" {
+		t.Errorf("invalid runs: expected one failed run got %+v %v", runs, err)
+	}
+}
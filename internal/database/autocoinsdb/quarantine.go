@@ -0,0 +1,74 @@
+package autocoinsdb
+
+import "time"
+
+// QuarantineState the state of a symbol that is quarantined by AutoCoins.
+type QuarantineState struct {
+	Symbol    string
+	Since     time.Time // Since the time the symbol was quarantined.
+	CleanRuns int       // CleanRuns the number of consecutive runs the symbol passed the release thresholds.
+}
+
+func (d *Database) CreateQuarantineTable() error {
+	query := "CREATE TABLE IF NOT EXISTS [QuarantineState] (Symbol TEXT NOT NULL PRIMARY KEY,Since INTEGER NOT NULL,CleanRuns INTEGER NOT NULL);"
+	_, err := d.db.Exec(query)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *Database) SelectQuarantineStates() ([]QuarantineState, error) {
+	rows, err := d.db.Query("SELECT Symbol, Since, CleanRuns FROM QuarantineState")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuarantineState
+	for rows.Next() {
+		var i QuarantineState
+		var since int64
+		if err := rows.Scan(
+			&i.Symbol,
+			&since,
+			&i.CleanRuns,
+		); err != nil {
+			return nil, err
+		}
+		i.Since = fromMillis(since)
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ReplaceQuarantineStates replaces all the stored states by the given states.
+func (d *Database) ReplaceQuarantineStates(items []QuarantineState) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM QuarantineState"); err != nil {
+		tx.Rollback()
+		return err
+	}
+	stmt, err := tx.Prepare("INSERT INTO QuarantineState(Symbol, Since, CleanRuns) values(?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, item := range items {
+		_, err = stmt.Exec(item.Symbol, toMillis(item.Since), item.CleanRuns)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
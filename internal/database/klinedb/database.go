@@ -0,0 +1,30 @@
+package klinedb
+
+import (
+	"database/sql"
+	"log"
+
+	_ "modernc.org/sqlite"
+)
+
+// Database stores the klines retrieved from the exchange.
+type Database struct {
+	db *sql.DB
+}
+
+func New(file string) *Database {
+	db, err := sql.Open("sqlite", file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Symbols are processed concurrently, SQLite only allows a single writer.
+	db.SetMaxOpenConns(1)
+
+	return &Database{
+		db: db,
+	}
+}
+
+func (d *Database) Close() error {
+	return d.db.Close()
+}
@@ -0,0 +1,91 @@
+package klinedb
+
+import (
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func (d *Database) CreateKlineTable() error {
+	query := "CREATE TABLE IF NOT EXISTS [Kline] (Symbol TEXT NOT NULL,Interval TEXT NOT NULL,OpenTime INTEGER NOT NULL,Open REAL NOT NULL,High REAL NOT NULL,Low REAL NOT NULL,Close REAL NOT NULL,Volume REAL NOT NULL,QuoteVolume REAL NOT NULL,PRIMARY KEY (Symbol, Interval, OpenTime));"
+	_, err := d.db.Exec(query)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SelectKlines returns the latest `limit` klines, oldest first.
+func (d *Database) SelectKlines(symbol string, interval exchange.KlineInterval, limit int) ([]exchange.Kline, error) {
+	stmt, err := d.db.Prepare("SELECT OpenTime, Open, High, Low, Close, Volume, QuoteVolume FROM (SELECT * FROM Kline WHERE Symbol = ? AND Interval = ? ORDER BY OpenTime DESC LIMIT ?) ORDER BY OpenTime ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.Query(symbol, string(interval), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []exchange.Kline
+	for rows.Next() {
+		var i exchange.Kline
+		var openTime int64
+		if err := rows.Scan(
+			&openTime,
+			&i.Open,
+			&i.High,
+			&i.Low,
+			&i.Close,
+			&i.Volume,
+			&i.QuoteVolume,
+		); err != nil {
+			return nil, err
+		}
+		i.OpenTime = time.Unix(0, openTime*int64(time.Millisecond))
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpsertKlines inserts the klines, existing klines with the same open time are replaced.
+func (d *Database) UpsertKlines(symbol string, interval exchange.KlineInterval, items []exchange.Kline) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("INSERT OR REPLACE INTO Kline(Symbol, Interval, OpenTime, Open, High, Low, Close, Volume, QuoteVolume) values(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, item := range items {
+		_, err = stmt.Exec(symbol, string(interval), item.OpenTime.UnixNano()/int64(time.Millisecond), item.Open, item.High, item.Low, item.Close, item.Volume, item.QuoteVolume)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteKlinesBefore removes the klines that opened before the given time.
+func (d *Database) DeleteKlinesBefore(symbol string, interval exchange.KlineInterval, before time.Time) error {
+	stmt, err := d.db.Prepare("DELETE FROM Kline WHERE Symbol = ? AND Interval = ? AND OpenTime < ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(symbol, string(interval), before.UnixNano()/int64(time.Millisecond))
+	if err != nil {
+		return err
+	}
+	return nil
+}
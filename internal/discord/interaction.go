@@ -0,0 +1,154 @@
+package discord
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultAPIURL the url of the Discord REST API.
+const DefaultAPIURL = "https://discord.com/api/v10"
+
+// Interaction types.
+const (
+	InteractionPing               = 1
+	InteractionApplicationCommand = 2
+)
+
+// Interaction response types.
+const (
+	ResponsePong                     = 1
+	ResponseChannelMessageWithSource = 4
+)
+
+// Application command option types.
+const (
+	OptionString = 3
+)
+
+// MessageFlagEphemeral only shows the response to the user that used the command.
+const MessageFlagEphemeral = 64
+
+// Interaction is sent by Discord to the interactions endpoint when a slash command is used.
+type Interaction struct {
+	ID      string                 `json:"id"`
+	Type    int                    `json:"type"`
+	Data    ApplicationCommandData `json:"data"`
+	GuildID string                 `json:"guild_id"`
+	Member  *Member                `json:"member"` // Member is set in a guild.
+	User    *User                  `json:"user"`   // User is set in a direct message.
+}
+
+type ApplicationCommandData struct {
+	Name    string              `json:"name"`
+	Options []InteractionOption `json:"options"`
+}
+
+// Option returns the value of the option, empty when it is not set.
+func (d *ApplicationCommandData) Option(name string) string {
+	for _, o := range d.Options {
+		if o.Name == name {
+			var value string
+			if err := json.Unmarshal(o.Value, &value); err != nil {
+				return string(o.Value)
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+type InteractionOption struct {
+	Name  string          `json:"name"`
+	Type  int             `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type Member struct {
+	User  User     `json:"user"`
+	Roles []string `json:"roles"`
+}
+
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// UserID returns the id of the user that used the command.
+func (i *Interaction) UserID() string {
+	if i.Member != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+type InteractionResponse struct {
+	Type int                      `json:"type"`
+	Data *InteractionResponseData `json:"data,omitempty"`
+}
+
+type InteractionResponseData struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []DiscordEmbed `json:"embeds,omitempty"`
+	Flags   int            `json:"flags,omitempty"`
+}
+
+// ApplicationCommand a slash command that is registered with Discord.
+type ApplicationCommand struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Options     []CommandOption `json:"options,omitempty"`
+}
+
+type CommandOption struct {
+	Type        int    `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// VerifyInteraction returns true when the body is signed by Discord with the public key of the application.
+func VerifyInteraction(publicKey ed25519.PublicKey, signature string, timestamp string, body []byte) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize || len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(publicKey, append([]byte(timestamp), body...), sig)
+}
+
+// RegisterCommands overwrites the slash commands of the application.
+// The commands are registered in the guild when a guild id is set (available immediately), otherwise globally.
+func RegisterCommands(apiURL string, token string, applicationID string, guildID string, commands []ApplicationCommand) error {
+	url := fmt.Sprintf("%s/applications/%s/commands", apiURL, applicationID)
+	if guildID != "" {
+		url = fmt.Sprintf("%s/applications/%s/guilds/%s/commands", apiURL, applicationID, guildID)
+	}
+	b, err := json.Marshal(commands)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bot "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to register Discord commands: %s", resp.Status)
+	}
+	return nil
+}
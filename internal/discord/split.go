@@ -0,0 +1,136 @@
+package discord
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// The limits of the Discord API.
+const (
+	MaxFieldNameLength  = 256
+	MaxFieldValueLength = 1024
+	MaxFieldsPerEmbed   = 25
+	MaxEmbedsPerMessage = 10
+	MaxMessageLength    = 6000 // MaxMessageLength the maximum number of characters of all embeds of a message.
+	MaxContentLength    = 2000
+)
+
+// SplitField returns the value as multiple fields when it is too long, the value is split at the separator.
+// The name of the following fields ends with "(continued)".
+func SplitField(name string, value string, separator string) []DiscordEmbedField {
+	fields := []DiscordEmbedField{}
+	current := ""
+	add := func() {
+		n := name
+		if len(fields) > 0 {
+			n = name + " (continued)"
+		}
+		fields = append(fields, DiscordEmbedField{Name: truncate(n, MaxFieldNameLength), Value: current})
+		current = ""
+	}
+	for _, part := range strings.Split(value, separator) {
+		part = truncate(part, MaxFieldValueLength)
+		switch {
+		case current == "":
+			current = part
+		case utf8.RuneCountInString(current)+utf8.RuneCountInString(separator)+utf8.RuneCountInString(part) > MaxFieldValueLength:
+			add()
+			current = part
+		default:
+			current += separator + part
+		}
+	}
+	if current != "" || len(fields) == 0 {
+		add()
+	}
+	return fields
+}
+
+// Length returns the number of characters of the embed that count towards MaxMessageLength.
+func (e *DiscordEmbed) Length() int {
+	n := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+	for _, f := range e.Fields {
+		n += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+	}
+	return n
+}
+
+// SplitEmbed splits the fields of an embed that has too many fields or characters over multiple embeds.
+// The following embeds keep the color but not the title and description.
+func SplitEmbed(embed DiscordEmbed) []DiscordEmbed {
+	if len(embed.Fields) <= MaxFieldsPerEmbed && embed.Length() <= MaxMessageLength {
+		return []DiscordEmbed{embed}
+	}
+	current := embed
+	current.Fields = nil
+	embeds := []DiscordEmbed{}
+	for _, f := range embed.Fields {
+		length := utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+		if len(current.Fields) > 0 && (len(current.Fields) == MaxFieldsPerEmbed || current.Length()+length > MaxMessageLength) {
+			embeds = append(embeds, current)
+			current = DiscordEmbed{Color: embed.Color}
+		}
+		current.Fields = append(current.Fields, f)
+	}
+	return append(embeds, current)
+}
+
+// SplitMessage splits a message with too many embeds or characters into multiple messages.
+// Content that is too long is sent as multiple messages before the embeds.
+func SplitMessage(message DiscordWebhookMessage) []DiscordWebhookMessage {
+	messages := []DiscordWebhookMessage{}
+	contents := SplitContent(message.Content)
+	for _, content := range contents[:len(contents)-1] {
+		messages = append(messages, DiscordWebhookMessage{Content: content})
+	}
+	current := DiscordWebhookMessage{Content: contents[len(contents)-1]}
+	length := 0
+	for _, e := range message.Embeds {
+		for _, embed := range SplitEmbed(e) {
+			l := embed.Length()
+			if len(current.Embeds) > 0 && (len(current.Embeds) == MaxEmbedsPerMessage || length+l > MaxMessageLength) {
+				messages = append(messages, current)
+				current = DiscordWebhookMessage{}
+				length = 0
+			}
+			current.Embeds = append(current.Embeds, embed)
+			length += l
+		}
+	}
+	return append(messages, current)
+}
+
+// SplitContent splits the content into parts of at most MaxContentLength characters, the content is split
+// at line breaks and lines that are too long are split at MaxContentLength.
+func SplitContent(content string) []string {
+	parts := []string{}
+	current := ""
+	for _, line := range strings.Split(content, "\n") {
+		for utf8.RuneCountInString(line) > MaxContentLength {
+			if current != "" {
+				parts = append(parts, current)
+				current = ""
+			}
+			runes := []rune(line)
+			parts = append(parts, string(runes[:MaxContentLength]))
+			line = string(runes[MaxContentLength:])
+		}
+		switch {
+		case current == "":
+			current = line
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(line) > MaxContentLength:
+			parts = append(parts, current)
+			current = line
+		default:
+			current += "\n" + line
+		}
+	}
+	return append(parts, current)
+}
+
+func truncate(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max-1]) + "…"
+}
@@ -0,0 +1,121 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func symbols(n int) string {
+	list := make([]string, n)
+	for i := range list {
+		list[i] = fmt.Sprintf("COIN%03dUSDT (1hr, 4hr)", i)
+	}
+	return strings.Join(list, ", ")
+}
+
+func TestSplitField(t *testing.T) {
+	fields := SplitField("Quarantined", symbols(200), ", ")
+	if len(fields) < 2 {
+		t.Fatalf("invalid number of fields: expected more than 1 got %d", len(fields))
+	}
+	if fields[0].Name != "Quarantined" || fields[1].Name != "Quarantined (continued)" {
+		t.Errorf("invalid field names: got %s, %s", fields[0].Name, fields[1].Name)
+	}
+	joined := []string{}
+	for _, f := range fields {
+		if utf8.RuneCountInString(f.Value) > MaxFieldValueLength {
+			t.Errorf("field too long: got %d characters", utf8.RuneCountInString(f.Value))
+		}
+		joined = append(joined, f.Value)
+	}
+	if strings.Join(joined, ", ") != symbols(200) {
+		t.Errorf("the fields do not contain all values")
+	}
+
+	fields = SplitField("Quarantined", "-", ", ")
+	if len(fields) != 1 || fields[0].Value != "-" {
+		t.Errorf("invalid short field: got %+v", fields)
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	message := DiscordWebhookMessage{
+		Content: "report",
+		Embeds: []DiscordEmbed{
+			{Title: "AutoCoins MarketSwing report", Description: "Generated"},
+			{Color: 1, Fields: SplitField("Quarantined", symbols(1000), ", ")},
+		},
+	}
+	messages := SplitMessage(message)
+	if len(messages) < 2 {
+		t.Fatalf("invalid number of messages: expected more than 1 got %d", len(messages))
+	}
+	if messages[0].Content != "report" || messages[1].Content != "" {
+		t.Errorf("the content should only be sent with the first message")
+	}
+	fields := 0
+	for _, m := range messages {
+		if len(m.Embeds) > MaxEmbedsPerMessage {
+			t.Errorf("too many embeds: got %d", len(m.Embeds))
+		}
+		length := 0
+		for _, e := range m.Embeds {
+			if len(e.Fields) > MaxFieldsPerEmbed {
+				t.Errorf("too many fields: got %d", len(e.Fields))
+			}
+			if e.Fields != nil && e.Color != 1 {
+				t.Errorf("the split embeds should keep the color")
+			}
+			length += e.Length()
+			fields += len(e.Fields)
+		}
+		if length > MaxMessageLength {
+			t.Errorf("message too long: got %d characters", length)
+		}
+	}
+	if expected := len(message.Embeds[1].Fields); fields != expected {
+		t.Errorf("invalid number of fields: expected %d got %d", expected, fields)
+	}
+}
+
+func TestSplitContent(t *testing.T) {
+	lines := make([]string, 300)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("Failed to update COIN%03dUSDT: permission denied", i)
+	}
+	content := strings.Join(lines, "\n")
+	parts := SplitContent(content)
+	if len(parts) < 2 {
+		t.Fatalf("invalid number of parts: expected more than 1 got %d", len(parts))
+	}
+	for _, p := range parts {
+		if utf8.RuneCountInString(p) > MaxContentLength {
+			t.Errorf("content too long: got %d characters", utf8.RuneCountInString(p))
+		}
+	}
+	if strings.Join(parts, "\n") != content {
+		t.Errorf("the parts do not contain all lines")
+	}
+
+	long := strings.Repeat("é", MaxContentLength+10)
+	parts = SplitContent(long)
+	if len(parts) != 2 || strings.Join(parts, "") != long {
+		t.Errorf("invalid split of a long line: got %d parts", len(parts))
+	}
+	if parts = SplitContent(""); len(parts) != 1 || parts[0] != "" {
+		t.Errorf("invalid split of empty content: got %v", parts)
+	}
+}
+
+func TestSplitMessageContent(t *testing.T) {
+	content := strings.Repeat("x", MaxContentLength) + "\nrest"
+	messages := SplitMessage(DiscordWebhookMessage{Content: content, Embeds: []DiscordEmbed{{Title: "report"}}})
+	if len(messages) != 2 {
+		t.Fatalf("invalid number of messages: expected %d got %d", 2, len(messages))
+	}
+	if len(messages[0].Embeds) != 0 || messages[1].Content != "rest" || len(messages[1].Embeds) != 1 {
+		t.Errorf("the embeds should be sent with the last part of the content: got %+v", messages)
+	}
+}
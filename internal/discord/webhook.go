@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type DiscordWebHook struct {
@@ -18,26 +20,54 @@ func (w *DiscordWebHook) SendTextMessage(message string) error {
 	})
 }
 
+// SendMessage sends the message, a message that exceeds the limits of Discord is sent as multiple messages.
 func (w *DiscordWebHook) SendMessage(message DiscordWebhookMessage) error {
 	if !w.Enabled || w.URL == "" {
 		return nil
 	}
 
-	b, err := json.Marshal(message)
-	if err != nil {
-		return err
+	for _, m := range SplitMessage(message) {
+		if err := w.post(m); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	reader := bytes.NewReader(b)
-	resp, err := http.Post(w.URL, "application/json", reader)
+// post sends one message, when rate limited it waits and tries once more.
+func (w *DiscordWebHook) post(message DiscordWebhookMessage) error {
+	b, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return fmt.Errorf("failed to post Discord webhook: %s", resp.Status)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := http.Post(w.URL, "application/json", bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusTooManyRequests && attempt == 0 {
+			time.Sleep(retryAfter(resp.Header.Get("Retry-After")))
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("failed to post Discord webhook: %s", resp.Status)
+		}
+		return nil
 	}
+}
 
-	return nil
+// retryAfter returns the duration of the Retry-After header in seconds, at most 10 seconds.
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds <= 0 {
+		return time.Second
+	}
+	if seconds > 10 {
+		seconds = 10
+	}
+	return time.Duration(seconds * float64(time.Second))
 }
 
 func (w *DiscordWebHook) SendError(message string, mention bool) error {
@@ -9,13 +9,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/logger"
 )
 
 type API struct {
@@ -27,6 +28,8 @@ type API struct {
 	LastWeightUpdate     time.Time // LastWeightUpdate time when `X-Mbx-Used-Weight-1m` header was last read.
 	EstimatedWeightUsage int       // EstimatedWeightUsage when this value is exceeded throttle the requests.
 	WeightWarning        bool      // WeightWarning indicates to pause requests until the warning is over.
+	FundingRates         bool      // FundingRates includes the funding rate request in the weight estimation.
+	OpenInterest         bool      // OpenInterest includes the open interest requests in the weight estimation.
 	client               http.Client
 	context              context.Context
 	cancel               context.CancelFunc
@@ -41,7 +44,8 @@ type APIParams struct {
 	ProxyPassword      string
 }
 
-func NewAPI(params APIParams) *API {
+// NewAPI creates the API client, returns an error when the proxy url is invalid.
+func NewAPI(params APIParams) (*API, error) {
 	api := API{
 		DebugSaveResponses: params.DebugSaveResponses,
 		DebugReadResponses: params.DebugReadResponses,
@@ -63,7 +67,7 @@ func NewAPI(params APIParams) *API {
 	if params.ProxyURL != "" {
 		proxyURL, err := url.Parse(params.ProxyURL)
 		if err != nil {
-			log.Fatalf("Invalid proxy url: %s\n", err.Error())
+			return nil, fmt.Errorf("invalid proxy url: %s", err.Error())
 		}
 		if params.ProxyUser != "" {
 			proxyURL.User = url.UserPassword(params.ProxyUser, params.ProxyPassword)
@@ -78,7 +82,7 @@ func NewAPI(params APIParams) *API {
 	api.cancel = cancel
 	api.client = client
 
-	return &api
+	return &api, nil
 }
 
 func (a *API) Cancel() {
@@ -227,15 +231,25 @@ func (a *API) GetKLine(symbol Symbol, limit int, interval KlineInterval) ([]KLin
 	url := fmt.Sprintf("%s/fapi/v1/klines?symbol=%s&interval=%s&limit=%s", a.BaseURL, symbol.Name, interval, l)
 	r, err := a.requestGet(url, false)
 	if err != nil {
-		log.Printf("ERROR: GetKLine:requestGet: %s\n", err.Error())
+		logger.Errorf("GetKLine:requestGet: %s\n", err.Error())
 		return nil, err
 	}
 
 	responseData := a.handleResponse(url, r.Body)
 
+	klines, err := parseKLines(responseData)
+	if err != nil {
+		logger.Errorf("GetKLine:Unmarshal: %s\n", err.Error())
+		return nil, err
+	}
+
+	return klines, nil
+}
+
+// parseKLines parses the kline response, the values of a kline are in an array.
+func parseKLines(responseData []byte) ([]KLine, error) {
 	var data [][]interface{}
 	if err := json.Unmarshal(responseData, &data); err != nil {
-		log.Printf("ERROR: GetKLine:Unmarshal: %s\n", err.Error())
 		return nil, err
 	}
 	klines := []KLine{}
@@ -244,29 +258,29 @@ func (a *API) GetKLine(symbol Symbol, limit int, interval KlineInterval) ([]KLin
 		for i, v := range d {
 			switch i {
 			case 0:
-				kline.OpenTime = a.klineInt64Value(v)
+				kline.OpenTime = klineInt64Value(v)
 			case 1:
-				kline.Open = a.klineStringValue(v)
+				kline.Open = klineStringValue(v)
 			case 2:
-				kline.High = a.klineStringValue(v)
+				kline.High = klineStringValue(v)
 			case 3:
-				kline.Low = a.klineStringValue(v)
+				kline.Low = klineStringValue(v)
 			case 4:
-				kline.Close = a.klineStringValue(v)
+				kline.Close = klineStringValue(v)
 			case 5:
-				kline.Volume = a.klineStringValue(v)
+				kline.Volume = klineStringValue(v)
 			case 6:
-				kline.CloseTime = a.klineInt64Value(v)
+				kline.CloseTime = klineInt64Value(v)
 			case 7:
-				kline.QuoteAssetVolume = a.klineStringValue(v)
+				kline.QuoteAssetVolume = klineStringValue(v)
 			case 8:
-				kline.NumberOfTrades = a.klineInt64Value(v)
+				kline.NumberOfTrades = klineInt64Value(v)
 			case 9:
-				kline.TakerBuyBaseAssetVolume = a.klineStringValue(v)
+				kline.TakerBuyBaseAssetVolume = klineStringValue(v)
 			case 10:
-				kline.TakerBuyQuoteAssetVolume = a.klineStringValue(v)
+				kline.TakerBuyQuoteAssetVolume = klineStringValue(v)
 			case 11:
-				kline.Ignore = a.klineStringValue(v)
+				kline.Ignore = klineStringValue(v)
 			}
 		}
 		klines = append(klines, kline)
@@ -275,7 +289,7 @@ func (a *API) GetKLine(symbol Symbol, limit int, interval KlineInterval) ([]KLin
 	return klines, nil
 }
 
-func (a *API) klineStringValue(value interface{}) string {
+func klineStringValue(value interface{}) string {
 	switch v := value.(type) {
 	case string:
 		return v
@@ -287,7 +301,7 @@ func (a *API) klineStringValue(value interface{}) string {
 	return ""
 }
 
-func (a *API) klineInt64Value(value interface{}) int64 {
+func klineInt64Value(value interface{}) int64 {
 	switch v := value.(type) {
 	case int64:
 		return v
@@ -323,13 +337,14 @@ func (a *API) requestGet(url string, skipWeightCheck bool) (*http.Response, erro
 			if float64(a.UsedWeight) > limit {
 				// TODO: not print warning for each request, too much spam.
 				// TODO: put in a while?
-				log.Printf("WARNING: exceeding weight limit: %d/%d\n", a.UsedWeight, a.EstimatedWeightUsage)
+				logger.Warnf("exceeding weight limit: %d/%d\n", a.UsedWeight, a.EstimatedWeightUsage)
 				if !a.pauseRequest(60 * time.Second) {
 					return nil, errors.New("context cancelled")
 				}
 			}
 		}
 
+		logger.Debugf("GET %s\n", url)
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
 			return nil, err
@@ -381,7 +396,7 @@ func (a *API) handleResponse(url string, body io.ReadCloser) []byte {
 	filename := FilenameForURL(url)
 	err := os.WriteFile(filename, data, 0644)
 	if err != nil {
-		log.Fatal(err)
+		logger.Errorf("unable to save response: %s\n", err.Error())
 	}
 
 	return data
@@ -0,0 +1,117 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+type PremiumIndex struct {
+	Symbol               string `json:"symbol"`
+	MarkPrice            string `json:"markPrice"`
+	IndexPrice           string `json:"indexPrice"`
+	EstimatedSettlePrice string `json:"estimatedSettlePrice"`
+	LastFundingRate      string `json:"lastFundingRate"`
+	InterestRate         string `json:"interestRate"`
+	NextFundingTime      int64  `json:"nextFundingTime"`
+	Time                 int64  `json:"time"`
+}
+
+type OpenInterestHist struct {
+	Symbol               string `json:"symbol"`
+	SumOpenInterest      string `json:"sumOpenInterest"`
+	SumOpenInterestValue string `json:"sumOpenInterestValue"`
+	Timestamp            int64  `json:"timestamp"`
+}
+
+// GetPremiumIndex get the mark price and funding rate for all symbols.
+// https://binance-docs.github.io/apidocs/futures/en/#mark-price
+func (a *API) GetPremiumIndex() ([]PremiumIndex, error) {
+	url := a.BaseURL + "/fapi/v1/premiumIndex"
+	r, err := a.requestGet(url, false)
+	if err != nil {
+		return nil, err
+	}
+
+	data := a.handleResponse(url, r.Body)
+
+	var premiumIndex []PremiumIndex
+	if err := json.Unmarshal(data, &premiumIndex); err != nil {
+		return nil, err
+	}
+
+	return premiumIndex, nil
+}
+
+// GetOpenInterestHist get the open interest statistics of a symbol.
+// Period is one of "5m","15m","30m","1h","2h","4h","6h","12h","1d", the maximum limit is 500.
+// https://binance-docs.github.io/apidocs/futures/en/#open-interest-statistics
+func (a *API) GetOpenInterestHist(symbol Symbol, period string, limit int) ([]OpenInterestHist, error) {
+	url := fmt.Sprintf("%s/futures/data/openInterestHist?symbol=%s&period=%s&limit=%d", a.BaseURL, symbol.Name, period, limit)
+	r, err := a.requestGet(url, false)
+	if err != nil {
+		return nil, err
+	}
+
+	data := a.handleResponse(url, r.Body)
+
+	var openInterestHist []OpenInterestHist
+	if err := json.Unmarshal(data, &openInterestHist); err != nil {
+		return nil, err
+	}
+
+	return openInterestHist, nil
+}
+
+// GetFundingRates implements exchange.DerivativesService.
+// A symbol of which the funding rate can not be parsed is skipped.
+func (s *Service) GetFundingRates() ([]exchange.FundingRate, error) {
+	premiumIndex, err := s.API.GetPremiumIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]exchange.FundingRate, 0, len(premiumIndex))
+	for _, p := range premiumIndex {
+		values, err := parseFloats(p.LastFundingRate)
+		if err != nil {
+			logger.Warnf("Skipping funding rate %s: %s\n", p.Symbol, err.Error())
+			continue
+		}
+		rates = append(rates, exchange.FundingRate{
+			Symbol:          p.Symbol,
+			Rate:            values[0],
+			NextFundingTime: time.Unix(0, p.NextFundingTime*int64(time.Millisecond)),
+		})
+	}
+	return rates, nil
+}
+
+// GetOpenInterestHistory implements exchange.DerivativesService.
+// Uses 5 minute periods, the history is limited to the last 41 hours.
+func (s *Service) GetOpenInterestHistory(symbol exchange.Symbol, hours int) ([]exchange.OpenInterest, error) {
+	limit := hours*12 + 1
+	if limit > 500 {
+		limit = 500
+	}
+	history, err := s.API.GetOpenInterestHist(Symbol{Name: symbol.Name}, "5m", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]exchange.OpenInterest, 0, len(history))
+	for _, h := range history {
+		v, err := parseFloats(h.SumOpenInterest)
+		if err != nil {
+			return nil, fmt.Errorf("open interest %s: %s", symbol.Name, err.Error())
+		}
+		values = append(values, exchange.OpenInterest{
+			Time:  time.Unix(0, h.Timestamp*int64(time.Millisecond)),
+			Value: v[0],
+		})
+	}
+	return values, nil
+}
@@ -0,0 +1,49 @@
+package binance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetFundingRatesSkipsInvalid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]PremiumIndex{
+			{Symbol: "AAAUSDT", LastFundingRate: "0.0001", NextFundingTime: 1640000000000},
+			{Symbol: "BBBUSDT", LastFundingRate: ""},
+		})
+	}))
+	defer server.Close()
+
+	api, err := NewAPI(APIParams{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewAPI returned error: %s", err.Error())
+	}
+	rates, err := NewService(api).GetFundingRates()
+	if err != nil {
+		t.Fatalf("GetFundingRates returned error: %s", err.Error())
+	}
+	if len(rates) != 1 || rates[0].Symbol != "AAAUSDT" || rates[0].Rate != 0.0001 {
+		t.Errorf("GetFundingRates: expected only AAAUSDT got %+v", rates)
+	}
+}
+
+func TestRateLimitChecksDerivativesWeight(t *testing.T) {
+	api, err := NewAPI(APIParams{})
+	if err != nil {
+		t.Fatalf("NewAPI returned error: %s", err.Error())
+	}
+
+	api.RateLimitChecks(100)
+	if api.EstimatedWeightUsage != 350 {
+		t.Errorf("estimated weight: expected %d got %d", 350, api.EstimatedWeightUsage)
+	}
+
+	api.FundingRates = true
+	api.OpenInterest = true
+	api.RateLimitChecks(100)
+	if api.EstimatedWeightUsage != 460 {
+		t.Errorf("estimated weight with derivatives: expected %d got %d", 460, api.EstimatedWeightUsage)
+	}
+}
@@ -1,14 +1,17 @@
 package binance
 
 import (
-	"log"
 	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/logger"
 )
 
 const (
 	TickerWeight           = 40.0
 	ExchangeInfoWeight     = 10.0
 	KlineWeight            = 3.0  // This is combined for all the Kline requests AutoCoins does per symbol.
+	PremiumIndexWeight     = 10.0 // PremiumIndexWeight the funding rates of all symbols.
+	OpenInterestHistWeight = 1.0  // OpenInterestHistWeight the open interest request AutoCoins does per symbol.
 	WeightEstimationBuffer = 1.2  // WeightEstimationBuffer percentage of `EstimatedWeightUsage` to use in rate limit.
 	MinimumWeightLimit     = 0.5  // MinimumWeightLimit percentage for minimum weight limit warning.
 	MaximumWeightLimit     = 0.75 // MaximumWeightLimit percentage for maximum weight limit warning.
@@ -33,7 +36,7 @@ func (a *API) CheckForWeightLimit() float64 {
 // PreCheckForWeightLimit determines ahead of time if the rate limit will be exceeded.
 func (a *API) PreCheckForWeightLimit() bool {
 	limit := a.CheckForWeightLimit()
-	log.Printf("Binance API Weight - Used: %d Estimated: %d Limit: %.0f\n", a.UsedWeight, a.EstimatedWeightUsage, limit)
+	logger.Debugf("Binance API Weight - Used: %d Estimated: %d Limit: %.0f\n", a.UsedWeight, a.EstimatedWeightUsage, limit)
 	return float64(a.EstimatedWeightUsage+a.UsedWeight) > limit
 }
 
@@ -44,10 +47,17 @@ func (a *API) PauseForWeightWarning() {
 
 // RateLimitChecks sets the rate limit estimation and pauses execution when estimated weight will be exceeded.
 func (a *API) RateLimitChecks(symbolCount int) {
-	a.EstimatedWeightUsage = int((float64(symbolCount) * KlineWeight) + TickerWeight + ExchangeInfoWeight)
+	weight := (float64(symbolCount) * KlineWeight) + TickerWeight + ExchangeInfoWeight
+	if a.FundingRates {
+		weight += PremiumIndexWeight
+	}
+	if a.OpenInterest {
+		weight += float64(symbolCount) * OpenInterestHistWeight
+	}
+	a.EstimatedWeightUsage = int(weight)
 	if a.PreCheckForWeightLimit() {
-		log.Println("Weight warning! Will pause for one minute")
+		logger.Warnf("Weight warning! Will pause for one minute")
 		a.PauseForWeightWarning()
-		log.Println("Finished weight wait")
+		logger.Infof("Finished weight wait")
 	}
 }
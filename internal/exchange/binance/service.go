@@ -0,0 +1,171 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// Service implements exchange.ExchangeService using the Binance Futures API.
+type Service struct {
+	API *API
+}
+
+func NewService(api *API) *Service {
+	return &Service{API: api}
+}
+
+func (s *Service) GetSymbols() ([]exchange.Symbol, error) {
+	exchangeInfo, err := s.API.GetExchangeInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]exchange.Symbol, 0, len(exchangeInfo.Symbols))
+	for _, symbol := range exchangeInfo.Symbols {
+		symbols = append(symbols, symbol.toExchange())
+	}
+	return symbols, nil
+}
+
+func (s *Service) GetTickers() ([]exchange.Ticker, error) {
+	tickers, err := s.API.GetTicker()
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTickers(tickers), nil
+}
+
+// convertTickers converts the tickers, a ticker that can not be parsed is skipped.
+func convertTickers(tickers []Ticker) []exchange.Ticker {
+	values := make([]exchange.Ticker, 0, len(tickers))
+	for _, t := range tickers {
+		ticker, err := t.toExchange()
+		if err != nil {
+			logger.Warnf("Skipping ticker %s: %s\n", t.Symbol, err.Error())
+			continue
+		}
+		values = append(values, ticker)
+	}
+	return values
+}
+
+func (s *Service) GetKline(symbol exchange.Symbol, interval exchange.KlineInterval, limit int) ([]exchange.Kline, error) {
+	klines, err := s.API.GetKLine(Symbol{Name: symbol.Name}, limit, KlineInterval(interval))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]exchange.Kline, 0, len(klines))
+	for _, k := range klines {
+		kline, err := k.toExchange()
+		if err != nil {
+			return nil, fmt.Errorf("kline %s: %s", symbol.Name, err.Error())
+		}
+		values = append(values, kline)
+	}
+	return values, nil
+}
+
+func (s *Service) RateLimitChecks(symbolCount int) {
+	s.API.RateLimitChecks(symbolCount)
+}
+
+func (s *Service) Weight() exchange.Weight {
+	return exchange.Weight{
+		Used:  s.API.UsedWeight,
+		Limit: s.API.WeightLimit,
+	}
+}
+
+func (s *Service) Cancel() {
+	s.API.Cancel()
+}
+
+func (s Symbol) toExchange() exchange.Symbol {
+	return exchange.Symbol{
+		Name:        s.Name,
+		BaseAsset:   s.BaseAsset,
+		QuoteAsset:  s.QuoteAsset,
+		MarginAsset: s.MarginAsset,
+		OnboardDate: time.Unix(0, s.OnboardDate*int64(time.Millisecond)),
+	}
+}
+
+func (t Ticker) toExchange() (exchange.Ticker, error) {
+	values, err := parseFloats(t.LastPrice, t.PriceChangePercent, t.Volume, t.QuoteVolume)
+	if err != nil {
+		return exchange.Ticker{}, err
+	}
+	return exchange.Ticker{
+		Symbol:                t.Symbol,
+		LastPrice:             values[0],
+		PriceChangePercent24h: values[1],
+		Volume:                values[2],
+		QuoteVolume:           values[3],
+		Count:                 t.Count,
+	}, nil
+}
+
+// ParseExchangeInfo parses a saved response of the exchange information endpoint.
+func ParseExchangeInfo(data []byte) ([]exchange.Symbol, error) {
+	var exchangeInfo ExchangeInfo
+	if err := json.Unmarshal(data, &exchangeInfo); err != nil {
+		return nil, err
+	}
+	symbols := make([]exchange.Symbol, 0, len(exchangeInfo.Symbols))
+	for _, symbol := range exchangeInfo.Symbols {
+		symbols = append(symbols, symbol.toExchange())
+	}
+	return symbols, nil
+}
+
+// ParseKlines parses a saved response of the kline endpoint.
+func ParseKlines(data []byte) ([]exchange.Kline, error) {
+	raw, err := parseKLines(data)
+	if err != nil {
+		return nil, err
+	}
+	klines := make([]exchange.Kline, 0, len(raw))
+	for _, k := range raw {
+		kline, err := k.toExchange()
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, kline)
+	}
+	return klines, nil
+}
+
+func (k KLine) toExchange() (exchange.Kline, error) {
+	values, err := parseFloats(k.Open, k.High, k.Low, k.Close, k.Volume, k.QuoteAssetVolume)
+	if err != nil {
+		return exchange.Kline{}, err
+	}
+	return exchange.Kline{
+		OpenTime:    time.Unix(0, k.OpenTime*int64(time.Millisecond)),
+		Open:        values[0],
+		High:        values[1],
+		Low:         values[2],
+		Close:       values[3],
+		Volume:      values[4],
+		QuoteVolume: values[5],
+	}, nil
+}
+
+func parseFloats(values ...string) ([]float64, error) {
+	floats := make([]float64, len(values))
+	for i, v := range values {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, err
+		}
+		floats[i] = f
+	}
+	return floats, nil
+}
@@ -0,0 +1,14 @@
+package binance
+
+import "testing"
+
+func TestConvertTickersSkipsInvalid(t *testing.T) {
+	tickers := []Ticker{
+		{Symbol: "AAAUSDT", LastPrice: "1.5", PriceChangePercent: "2", Volume: "100", QuoteVolume: "150"},
+		{Symbol: "BBBUSDT", LastPrice: "invalid", PriceChangePercent: "2", Volume: "100", QuoteVolume: "150"},
+	}
+	values := convertTickers(tickers)
+	if len(values) != 1 || values[0].Symbol != "AAAUSDT" || values[0].LastPrice != 1.5 {
+		t.Errorf("convertTickers: expected only AAAUSDT got %+v", values)
+	}
+}
@@ -0,0 +1,180 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	StreamsPerConnection = 200              // StreamsPerConnection maximum number of streams Binance allows on one connection.
+	StreamReadTimeout    = 60 * time.Second // StreamReadTimeout when no message is received within this time the connection is considered lost.
+	miniTickerStream     = "!miniTicker@arr"
+)
+
+// Stream implements exchange.StreamService using the Binance Futures WebSocket market streams.
+// https://binance-docs.github.io/apidocs/futures/en/#websocket-market-streams
+type Stream struct {
+	BaseURL string // BaseURL the base url for the streams (wss://fstream.binance.com).
+}
+
+func NewStream(baseURL string) *Stream {
+	return &Stream{BaseURL: baseURL}
+}
+
+type streamMessage struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type streamKlineEvent struct {
+	Symbol string `json:"s"`
+	Kline  struct {
+		OpenTime    int64  `json:"t"`
+		Open        string `json:"o"`
+		High        string `json:"h"`
+		Low         string `json:"l"`
+		Close       string `json:"c"`
+		Volume      string `json:"v"`
+		QuoteVolume string `json:"q"`
+	} `json:"k"`
+}
+
+type streamMiniTicker struct {
+	Symbol      string `json:"s"`
+	Close       string `json:"c"`
+	Open        string `json:"o"`
+	Volume      string `json:"v"`
+	QuoteVolume string `json:"q"`
+}
+
+// Stream opens a connection for every `StreamsPerConnection` streams.
+// When one of the connections is lost all connections are closed.
+func (s *Stream) Stream(ctx context.Context, symbols []string, handler exchange.StreamHandler) error {
+	streams := []string{miniTickerStream}
+	for _, symbol := range symbols {
+		streams = append(streams, strings.ToLower(symbol)+"@kline_1m")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	count := 0
+	errs := make(chan error)
+	for start := 0; start < len(streams); start += StreamsPerConnection {
+		end := start + StreamsPerConnection
+		if end > len(streams) {
+			end = len(streams)
+		}
+		go func(streams []string) {
+			errs <- s.connect(ctx, streams, handler)
+		}(streams[start:end])
+		count++
+	}
+
+	err := <-errs
+	cancel()
+	for i := 1; i < count; i++ {
+		<-errs
+	}
+	return err
+}
+
+func (s *Stream) connect(ctx context.Context, streams []string, handler exchange.StreamHandler) error {
+	config, err := websocket.NewConfig(s.BaseURL+"/stream?streams="+strings.Join(streams, "/"), "http://localhost/")
+	if err != nil {
+		return err
+	}
+	config.Dialer = &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(StreamReadTimeout))
+		var message streamMessage
+		if err := websocket.JSON.Receive(conn, &message); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if err := s.handleMessage(message, handler); err != nil {
+			return fmt.Errorf("stream %s: %s", message.Stream, err.Error())
+		}
+	}
+}
+
+// handleMessage passes the tickers and klines of the message to the handler.
+// Only a message that can not be decoded returns an error, a ticker or kline with invalid values is skipped.
+func (s *Stream) handleMessage(message streamMessage, handler exchange.StreamHandler) error {
+	switch {
+	case message.Stream == miniTickerStream:
+		var tickers []streamMiniTicker
+		if err := json.Unmarshal(message.Data, &tickers); err != nil {
+			return err
+		}
+		values := make([]exchange.Ticker, 0, len(tickers))
+		for _, t := range tickers {
+			v, err := parseFloats(t.Close, t.Open, t.Volume, t.QuoteVolume)
+			if err != nil {
+				logger.Warnf("Skipping streamed ticker %s: %s\n", t.Symbol, err.Error())
+				continue
+			}
+			percent := 0.0
+			if v[1] != 0 {
+				percent = (v[0] - v[1]) * 100 / v[1]
+			}
+			values = append(values, exchange.Ticker{
+				Symbol:                t.Symbol,
+				LastPrice:             v[0],
+				PriceChangePercent24h: percent,
+				Volume:                v[2],
+				QuoteVolume:           v[3],
+			})
+		}
+		handler.HandleTickers(values)
+	case strings.HasSuffix(message.Stream, "@kline_1m"):
+		var event streamKlineEvent
+		if err := json.Unmarshal(message.Data, &event); err != nil {
+			return err
+		}
+		k := event.Kline
+		v, err := parseFloats(k.Open, k.High, k.Low, k.Close, k.Volume, k.QuoteVolume)
+		if err != nil {
+			logger.Warnf("Skipping streamed kline %s: %s\n", event.Symbol, err.Error())
+			return nil
+		}
+		handler.HandleKline(event.Symbol, exchange.Kline{
+			OpenTime:    time.Unix(0, k.OpenTime*int64(time.Millisecond)),
+			Open:        v[0],
+			High:        v[1],
+			Low:         v[2],
+			Close:       v[3],
+			Volume:      v[4],
+			QuoteVolume: v[5],
+		})
+	default:
+		return errors.New("unknown stream")
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package binance
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"golang.org/x/net/websocket"
+)
+
+type recordingHandler struct {
+	mutex   sync.Mutex
+	klines  []exchange.Kline
+	tickers []exchange.Ticker
+}
+
+func (h *recordingHandler) HandleKline(symbol string, kline exchange.Kline) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.klines = append(h.klines, kline)
+}
+
+func (h *recordingHandler) HandleTickers(tickers []exchange.Ticker) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.tickers = append(h.tickers, tickers...)
+}
+
+func TestStream(t *testing.T) {
+	messages := []string{
+		`{"stream":"testusdt@kline_1m","data":{"e":"kline","s":"TESTUSDT","k":{"t":1630000000000,"o":"1.0","h":"1.5","l":"0.5","c":"1.2","v":"100","q":"120","x":false}}}`,
+		`{"stream":"!miniTicker@arr","data":[{"e":"24hrMiniTicker","s":"TESTUSDT","c":"1.1","o":"1.0","v":"1000","q":"1100"}]}`,
+	}
+	var query string
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		query = conn.Request().URL.RawQuery
+		for _, m := range messages {
+			websocket.Message.Send(conn, m)
+		}
+	}))
+	defer server.Close()
+
+	handler := &recordingHandler{}
+	stream := NewStream("ws" + strings.TrimPrefix(server.URL, "http"))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := stream.Stream(ctx, []string{"TESTUSDT"}, handler); err == nil {
+		t.Fatalf("expected error when connection is closed")
+	}
+
+	if query != "streams=!miniTicker@arr/testusdt@kline_1m" {
+		t.Errorf("invalid streams: %s", query)
+	}
+	if len(handler.klines) != 1 || handler.klines[0].High != 1.5 || handler.klines[0].OpenTime.Unix() != 1630000000 {
+		t.Errorf("invalid klines: %+v", handler.klines)
+	}
+	if len(handler.tickers) != 1 || handler.tickers[0].PriceChangePercent24h < 9.99 || handler.tickers[0].PriceChangePercent24h > 10.01 {
+		t.Errorf("invalid tickers: %+v", handler.tickers)
+	}
+}
+
+func TestHandleMessageSkipsInvalid(t *testing.T) {
+	s := NewStream("")
+	handler := &recordingHandler{}
+
+	tickers := streamMessage{
+		Stream: miniTickerStream,
+		Data:   []byte(`[{"s":"AAAUSDT","c":"1.1","o":"1.0","v":"1000","q":"1100"},{"s":"BBBUSDT","c":"","o":"1.0","v":"1000","q":"1100"}]`),
+	}
+	if err := s.handleMessage(tickers, handler); err != nil {
+		t.Errorf("tickers: expected no error got %s", err.Error())
+	}
+	if len(handler.tickers) != 1 || handler.tickers[0].Symbol != "AAAUSDT" {
+		t.Errorf("invalid tickers: expected only AAAUSDT got %+v", handler.tickers)
+	}
+
+	kline := streamMessage{
+		Stream: "bbbusdt@kline_1m",
+		Data:   []byte(`{"s":"BBBUSDT","k":{"t":1630000000000,"o":"invalid","h":"1.5","l":"0.5","c":"1.2","v":"100","q":"120"}}`),
+	}
+	if err := s.handleMessage(kline, handler); err != nil {
+		t.Errorf("kline: expected no error got %s", err.Error())
+	}
+	if len(handler.klines) != 0 {
+		t.Errorf("invalid klines: expected none got %+v", handler.klines)
+	}
+
+	invalid := streamMessage{Stream: miniTickerStream, Data: []byte(`{"s":"AAAUSDT"}`)}
+	if err := s.handleMessage(invalid, handler); err == nil {
+		t.Errorf("expected error for a message that can not be decoded")
+	}
+}
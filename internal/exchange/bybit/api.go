@@ -1,21 +1,27 @@
-package bytbit
+package bybit
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 )
 
 type API struct {
-	BaseURL string
-	client  http.Client
-	context context.Context
-	cancel  context.CancelFunc
+	BaseURL          string        // BaseURL the base url for the ByBit API.
+	RequestInterval  time.Duration // RequestInterval minimum time between two requests.
+	UsedWeight       int           // UsedWeight number of requests done in the current minute.
+	LastWeightUpdate time.Time     // LastWeightUpdate start of the current minute.
+	client           http.Client
+	context          context.Context
+	cancel           context.CancelFunc
+	mutex            sync.Mutex
+	nextRequestTime  time.Time
 }
 
 type APIParams struct {
@@ -26,9 +32,11 @@ type APIParams struct {
 }
 
 // https://api.bybit.com
-func NewAPI(params APIParams) *API {
+// NewAPI creates the API client, returns an error when the proxy url is invalid.
+func NewAPI(params APIParams) (*API, error) {
 	api := API{
-		BaseURL: params.BaseURL,
+		BaseURL:         params.BaseURL,
+		RequestInterval: time.Second / RequestsPerSecond,
 	}
 	client := http.Client{
 		Timeout: time.Second * 10,
@@ -46,7 +54,7 @@ func NewAPI(params APIParams) *API {
 	if params.ProxyURL != "" {
 		proxyURL, err := url.Parse(params.ProxyURL)
 		if err != nil {
-			log.Fatalf("Invalid proxy url: %s\n", err.Error())
+			return nil, fmt.Errorf("invalid proxy url: %s", err.Error())
 		}
 		if params.ProxyUser != "" {
 			proxyURL.User = url.UserPassword(params.ProxyUser, params.ProxyPassword)
@@ -61,7 +69,7 @@ func NewAPI(params APIParams) *API {
 	api.cancel = cancel
 	api.client = client
 
-	return &api
+	return &api, nil
 }
 
 func (a *API) Cancel() {
@@ -76,6 +84,20 @@ type ByBitResponse struct {
 	TimeNow string `json:"time_now"`
 }
 
+func (r *ByBitResponse) response() *ByBitResponse {
+	return r
+}
+
+// ResponseError is returned when the API responds with a non zero `ret_code`.
+type ResponseError struct {
+	Code    int
+	Message string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("bybit error %d: %s", e.Code, e.Message)
+}
+
 type Symbol struct {
 	Name          string `json:"name"`
 	Alias         string `json:"alias"`
@@ -92,20 +114,11 @@ type SymbolsResponse struct {
 	Result []Symbol `json:"result"`
 }
 
+// GetSymbols returns all symbols (inverse and linear).
+// https://bybit-exchange.github.io/docs/linear/#t-querysymbol
 func (a *API) GetSymbols() ([]Symbol, error) {
-	url := a.BaseURL + "/v2/public/symbols"
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := a.client.Do(req.WithContext(a.context))
-	if err != nil {
-		return nil, err
-	}
-
 	var symbols SymbolsResponse
-	err = json.NewDecoder(resp.Body).Decode(&symbols)
-	if err != nil {
+	if err := a.requestGet(a.BaseURL+"/v2/public/symbols", &symbols); err != nil {
 		return nil, err
 	}
 
@@ -122,30 +135,25 @@ type Kline struct {
 	High     float64 `json:"high"`
 	Low      float64 `json:"low"`
 	Close    float64 `json:"close"`
-	Interval int     `json:"interval"`
+	Interval string  `json:"interval"`
 	OpenTime int64   `json:"open_time"`
 	Turnover float64 `json:"turnover"`
 }
 
 type KlineResponse struct {
 	ByBitResponse
-	Result []Kline
+	Result []Kline `json:"result"`
 }
 
-func (a *API) GetKline(symbol, interval string, from, limit int) ([]Kline, error) {
-	url := fmt.Sprintf("%s/public/linear/kline?symbol=%s&interval=%sfrom=%dlimit=%d", a.BaseURL, symbol, interval, from, limit)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := a.client.Do(req.WithContext(a.context))
-	if err != nil {
-		return nil, err
-	}
+// KlineLimit maximum number of klines returned in one request.
+const KlineLimit = 200
 
+// GetKline returns at most `limit` klines starting at `from` (unix seconds) for an USDT perpetual.
+// https://bybit-exchange.github.io/docs/linear/#t-querykline
+func (a *API) GetKline(symbol, interval string, from int64, limit int) ([]Kline, error) {
+	url := fmt.Sprintf("%s/public/linear/kline?symbol=%s&interval=%s&from=%d&limit=%d", a.BaseURL, symbol, interval, from, limit)
 	var klines KlineResponse
-	err = json.NewDecoder(resp.Body).Decode(&klines)
-	if err != nil {
+	if err := a.requestGet(url, &klines); err != nil {
 		return nil, err
 	}
 
@@ -153,32 +161,32 @@ func (a *API) GetKline(symbol, interval string, from, limit int) ([]Kline, error
 }
 
 type Ticker struct {
-	Symbol                 string `json:"symbol"`
-	BidPrice               string `json:"bid_price"`
-	AskPrice               string `json:"ask_price"`
-	LastPrice              string `json:"last_price"`
-	LastTickDirection      string `json:"last_tick_direction"`
-	PrevPrice24h           string `json:"prev_price_24h"`
-	Price24hPcnt           string `json:"price_24h_pcnt"`
-	HighPrice24h           string `json:"high_price_24h"`
-	LowPrice24h            string `json:"low_price_24h"`
-	PrevPrice1h            string `json:"prev_price_1h"`
-	Price1hPcnt            string `json:"price_1h_pcnt"`
-	MarkPrice              string `json:"mark_price"`
-	IndexPrice             string `json:"index_price"`
-	OpenInterest           int64  `json:"open_interest"`
-	OpenValue              string `json:"open_value"`
-	TotalTurnover          string `json:"total_turnover"`
-	Turnover24h            string `json:"turnover_24h"`
-	TotalVolume            int64  `json:"total_volume"`
-	Volume24h              int64  `json:"volume_24h"`
-	FundingRate            string `json:"funding_rate"`
-	PredictedFundingRate   string `json:"predicted_funding_rate"`
-	NextFundingTime        string `json:"next_funding_time"`
-	CountdownHour          string `json:"countdown_hour"`
-	DeliveryFeeRate        string `json:"delivery_fee_rate"`
-	PredictedDeliveryPrice string `json:"predicted_delivery_price"`
-	DeliveryTime           string `json:"delivery_time"`
+	Symbol                 string  `json:"symbol"`
+	BidPrice               string  `json:"bid_price"`
+	AskPrice               string  `json:"ask_price"`
+	LastPrice              string  `json:"last_price"`
+	LastTickDirection      string  `json:"last_tick_direction"`
+	PrevPrice24h           string  `json:"prev_price_24h"`
+	Price24hPcnt           string  `json:"price_24h_pcnt"`
+	HighPrice24h           string  `json:"high_price_24h"`
+	LowPrice24h            string  `json:"low_price_24h"`
+	PrevPrice1h            string  `json:"prev_price_1h"`
+	Price1hPcnt            string  `json:"price_1h_pcnt"`
+	MarkPrice              string  `json:"mark_price"`
+	IndexPrice             string  `json:"index_price"`
+	OpenInterest           float64 `json:"open_interest"`
+	OpenValue              string  `json:"open_value"`
+	TotalTurnover          string  `json:"total_turnover"`
+	Turnover24h            string  `json:"turnover_24h"`
+	TotalVolume            float64 `json:"total_volume"`
+	Volume24h              float64 `json:"volume_24h"`
+	FundingRate            string  `json:"funding_rate"`
+	PredictedFundingRate   string  `json:"predicted_funding_rate"`
+	NextFundingTime        string  `json:"next_funding_time"`
+	CountdownHour          float64 `json:"countdown_hour"`
+	DeliveryFeeRate        string  `json:"delivery_fee_rate"`
+	PredictedDeliveryPrice string  `json:"predicted_delivery_price"`
+	DeliveryTime           string  `json:"delivery_time"`
 }
 
 type TickerResponse struct {
@@ -186,22 +194,57 @@ type TickerResponse struct {
 	Result []Ticker `json:"result"`
 }
 
+// GetTicker returns the ticker for the symbol, or all tickers when symbol is empty.
+// https://bybit-exchange.github.io/docs/linear/#t-latestsymbolinfo
 func (a *API) GetTicker(symbol string) ([]Ticker, error) {
-	url := fmt.Sprintf("%s/v2/public/tickers?symbol=%s", a.BaseURL, symbol)
+	url := a.BaseURL + "/v2/public/tickers"
+	if symbol != "" {
+		url += "?symbol=" + symbol
+	}
+	var ticker TickerResponse
+	if err := a.requestGet(url, &ticker); err != nil {
+		return nil, err
+	}
+
+	return ticker.Result, nil
+}
+
+type apiResponse interface {
+	response() *ByBitResponse
+}
+
+// requestGet does a GET request and decodes the response into result.
+// It returns a ResponseError when the response contains a `ret_code` other than 0.
+func (a *API) requestGet(url string, result apiResponse) error {
+	if !a.throttle() {
+		return a.context.Err()
+	}
+
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	resp, err := a.client.Do(req.WithContext(a.context))
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer resp.Body.Close()
 
-	var ticker TickerResponse
-	err = json.NewDecoder(resp.Body).Decode(&ticker)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return ticker.Result, nil
+	if err := json.Unmarshal(body, result); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("response status code is '%d' (%s)", resp.StatusCode, resp.Status)
+		}
+		return err
+	}
+
+	if r := result.response(); r.RetCode != 0 {
+		return &ResponseError{Code: r.RetCode, Message: r.RetMsg}
+	}
+
+	return nil
 }
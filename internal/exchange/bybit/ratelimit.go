@@ -0,0 +1,51 @@
+package bybit
+
+import (
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+const (
+	RequestsPerSecond = 20                     // RequestsPerSecond stays well below the public limit of 50 requests per second per IP.
+	RequestsPerMinute = RequestsPerSecond * 60 // RequestsPerMinute used as weight limit.
+)
+
+// throttle spaces out the requests to stay below `RequestsPerSecond`.
+// Returns false when the API is cancelled while waiting.
+func (a *API) throttle() bool {
+	a.mutex.Lock()
+	now := time.Now()
+	if now.Sub(a.LastWeightUpdate) > time.Minute {
+		a.UsedWeight = 0
+		a.LastWeightUpdate = now
+	}
+	a.UsedWeight++
+
+	wait := a.nextRequestTime.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	a.nextRequestTime = now.Add(wait + a.RequestInterval)
+	a.mutex.Unlock()
+
+	select {
+	case <-a.context.Done():
+		return false
+	case <-time.After(wait):
+		return true
+	}
+}
+
+// RateLimitChecks logs the estimated number of requests. ByBit requests are throttled so it never pauses.
+func (a *API) RateLimitChecks(requestCount int) {
+	duration := time.Duration(requestCount) * a.RequestInterval
+	logger.Debugf("ByBit API Requests - Used: %d Estimated: %d (%s)\n", a.usedWeight(), requestCount, duration.Round(time.Second))
+}
+
+// usedWeight returns the number of requests done in the current minute.
+func (a *API) usedWeight() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.UsedWeight
+}
@@ -0,0 +1,203 @@
+package bybit
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// Service implements exchange.ExchangeService for the ByBit USDT perpetuals.
+type Service struct {
+	API          *API
+	onboardDates map[string]time.Time
+	mutex        sync.Mutex
+}
+
+func NewService(api *API) *Service {
+	return &Service{
+		API:          api,
+		onboardDates: map[string]time.Time{},
+	}
+}
+
+// onboardDateWorkers the number of onboard dates retrieved at the same time.
+const onboardDateWorkers = 10
+
+// intervals maps the generic kline intervals on the ByBit intervals and their duration.
+var intervals = map[exchange.KlineInterval]struct {
+	name     string
+	duration time.Duration
+}{
+	exchange.OneMinute: {"1", time.Minute},
+	exchange.OneHour:   {"60", time.Hour},
+	exchange.OneDay:    {"D", 24 * time.Hour},
+	exchange.OneMonth:  {"M", 31 * 24 * time.Hour},
+}
+
+// GetSymbols returns the trading USDT perpetuals.
+// ByBit does not return a listing date so it is taken from the first daily candle (and cached).
+// A symbol of which the listing date can not be retrieved is skipped.
+func (s *Service) GetSymbols() ([]exchange.Symbol, error) {
+	symbols, err := s.API.GetSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	trading := []Symbol{}
+	for _, symbol := range symbols {
+		if symbol.QuoteCurrency == "USDT" && symbol.Status == "Trading" {
+			trading = append(trading, symbol)
+		}
+	}
+
+	// The requests are throttled by the API, the workers only wait for the responses in parallel.
+	onboardDates := make([]time.Time, len(trading))
+	errs := make([]error, len(trading))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, onboardDateWorkers)
+	for i, symbol := range trading {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			onboardDates[i], errs[i] = s.onboardDate(name)
+		}(i, symbol.Name)
+	}
+	wg.Wait()
+
+	values := []exchange.Symbol{}
+	for i, symbol := range trading {
+		if errs[i] != nil {
+			logger.Warnf("Skipping %s, unable to get the onboard date: %s\n", symbol.Name, errs[i].Error())
+			continue
+		}
+		values = append(values, exchange.Symbol{
+			Name:        symbol.Name,
+			BaseAsset:   symbol.BaseCurrency,
+			QuoteAsset:  symbol.QuoteCurrency,
+			MarginAsset: symbol.QuoteCurrency,
+			OnboardDate: onboardDates[i],
+		})
+	}
+	return values, nil
+}
+
+func (s *Service) onboardDate(symbol string) (time.Time, error) {
+	s.mutex.Lock()
+	date, ok := s.onboardDates[symbol]
+	s.mutex.Unlock()
+	if ok {
+		return date, nil
+	}
+
+	klines, err := s.API.GetKline(symbol, intervals[exchange.OneDay].name, 0, 1)
+	if err != nil {
+		return time.Time{}, err
+	}
+	date = time.Now()
+	if len(klines) > 0 {
+		date = time.Unix(klines[0].StartAt, 0)
+	}
+
+	s.mutex.Lock()
+	s.onboardDates[symbol] = date
+	s.mutex.Unlock()
+	return date, nil
+}
+
+func (s *Service) GetTickers() ([]exchange.Ticker, error) {
+	tickers, err := s.API.GetTicker("")
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTickers(tickers), nil
+}
+
+// convertTickers converts the tickers, a ticker that can not be parsed is skipped.
+func convertTickers(tickers []Ticker) []exchange.Ticker {
+	values := make([]exchange.Ticker, 0, len(tickers))
+	for _, t := range tickers {
+		lastPrice, err := strconv.ParseFloat(t.LastPrice, 64)
+		if err != nil {
+			logger.Warnf("Skipping ticker %s: %s\n", t.Symbol, err.Error())
+			continue
+		}
+		percent, err := strconv.ParseFloat(t.Price24hPcnt, 64)
+		if err != nil {
+			logger.Warnf("Skipping ticker %s: %s\n", t.Symbol, err.Error())
+			continue
+		}
+		// Turnover is not available for every symbol.
+		turnover, _ := strconv.ParseFloat(t.Turnover24h, 64)
+		values = append(values, exchange.Ticker{
+			Symbol:                t.Symbol,
+			LastPrice:             lastPrice,
+			PriceChangePercent24h: percent * 100,
+			Volume:                t.Volume24h,
+			QuoteVolume:           turnover,
+		})
+	}
+	return values
+}
+
+// GetKline returns the latest `limit` klines. ByBit returns at most `KlineLimit` klines per request
+// so the klines are retrieved in pages starting from the oldest one needed.
+func (s *Service) GetKline(symbol exchange.Symbol, interval exchange.KlineInterval, limit int) ([]exchange.Kline, error) {
+	i, ok := intervals[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval '%s'", interval)
+	}
+
+	from := time.Now().Add(-time.Duration(limit) * i.duration).Unix()
+	klines := []Kline{}
+	for len(klines) < limit {
+		page, err := s.API.GetKline(symbol.Name, i.name, from, KlineLimit)
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, page...)
+		if len(page) < KlineLimit {
+			break
+		}
+		from = page[len(page)-1].StartAt + int64(i.duration.Seconds())
+	}
+	if len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+
+	values := make([]exchange.Kline, 0, len(klines))
+	for _, k := range klines {
+		values = append(values, exchange.Kline{
+			OpenTime:    time.Unix(k.StartAt, 0),
+			Open:        k.Open,
+			High:        k.High,
+			Low:         k.Low,
+			Close:       k.Close,
+			Volume:      k.Volume,
+			QuoteVolume: k.Turnover,
+		})
+	}
+	return values, nil
+}
+
+// RateLimitChecks estimates the requests needed for the 1 minute and 1 month klines.
+func (s *Service) RateLimitChecks(symbolCount int) {
+	s.API.RateLimitChecks(symbolCount * 3)
+}
+
+func (s *Service) Weight() exchange.Weight {
+	return exchange.Weight{
+		Used:  s.API.usedWeight(),
+		Limit: RequestsPerMinute,
+	}
+}
+
+func (s *Service) Cancel() {
+	s.API.Cancel()
+}
@@ -0,0 +1,112 @@
+package bybit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+// newTestService returns a service using a local stand-in for the ByBit kline endpoint.
+// The stand-in has one minute klines for the last 500 minutes.
+// The symbol list contains TESTUSDT and OTHERUSDT, only TESTUSDT has klines.
+func newTestService(t *testing.T) *Service {
+	end := time.Now().Truncate(time.Minute).Unix()
+	start := end - 500*60
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/public/symbols" {
+			json.NewEncoder(w).Encode(SymbolsResponse{Result: []Symbol{
+				{Name: "TESTUSDT", Status: "Trading", BaseCurrency: "TEST", QuoteCurrency: "USDT"},
+				{Name: "OTHERUSDT", Status: "Trading", BaseCurrency: "OTHER", QuoteCurrency: "USDT"},
+			}})
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("symbol") != "TESTUSDT" {
+			json.NewEncoder(w).Encode(ByBitResponse{RetCode: 10001, RetMsg: "params error: symbol invalid"})
+			return
+		}
+		from, _ := strconv.ParseInt(q.Get("from"), 10, 64)
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		if from < start {
+			from = start
+		}
+		response := KlineResponse{}
+		for ts := from - from%60; ts <= end && len(response.Result) < limit; ts += 60 {
+			response.Result = append(response.Result, Kline{Symbol: "TESTUSDT", StartAt: ts, Open: float64(ts)})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := NewAPI(APIParams{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewAPI returned error: %s", err.Error())
+	}
+	api.RequestInterval = 0
+	return NewService(api)
+}
+
+func TestGetKlinePaginated(t *testing.T) {
+	s := newTestService(t)
+
+	limit := 450
+	klines, err := s.GetKline(exchange.Symbol{Name: "TESTUSDT"}, exchange.OneMinute, limit)
+	if err != nil {
+		t.Fatalf("GetKline returned error: %s", err.Error())
+	}
+	if len(klines) != limit {
+		t.Fatalf("invalid kline count: expected %d got %d", limit, len(klines))
+	}
+	for i := 1; i < len(klines); i++ {
+		if klines[i].OpenTime.Sub(klines[i-1].OpenTime) != time.Minute {
+			t.Fatalf("klines not consecutive at %d: %s %s", i, klines[i-1].OpenTime, klines[i].OpenTime)
+		}
+	}
+}
+
+func TestGetKlineResponseError(t *testing.T) {
+	s := newTestService(t)
+
+	_, err := s.GetKline(exchange.Symbol{Name: "OTHERUSDT"}, exchange.OneMinute, 10)
+	var responseError *ResponseError
+	if !errors.As(err, &responseError) {
+		t.Fatalf("expected ResponseError got %v", err)
+	}
+	if responseError.Code != 10001 {
+		t.Errorf("invalid ret_code: expected %d got %d", 10001, responseError.Code)
+	}
+}
+
+func TestGetSymbolsSkipsMissingOnboardDate(t *testing.T) {
+	s := newTestService(t)
+
+	symbols, err := s.GetSymbols()
+	if err != nil {
+		t.Fatalf("GetSymbols returned error: %s", err.Error())
+	}
+	if len(symbols) != 1 || symbols[0].Name != "TESTUSDT" {
+		t.Fatalf("invalid symbols: expected [TESTUSDT] got %v", symbols)
+	}
+	if symbols[0].OnboardDate.IsZero() {
+		t.Errorf("onboard date not set for %s", symbols[0].Name)
+	}
+}
+
+func TestConvertTickersSkipsInvalid(t *testing.T) {
+	tickers := []Ticker{
+		{Symbol: "AAAUSDT", LastPrice: "1.5", Price24hPcnt: "0.02", Turnover24h: "150"},
+		{Symbol: "BBBUSDT", LastPrice: "invalid", Price24hPcnt: "0.02"},
+		{Symbol: "CCCUSDT", LastPrice: "2", Price24hPcnt: ""},
+	}
+	values := convertTickers(tickers)
+	if len(values) != 1 || values[0].Symbol != "AAAUSDT" || values[0].LastPrice != 1.5 || values[0].PriceChangePercent24h != 2 {
+		t.Errorf("convertTickers: expected only AAAUSDT got %+v", values)
+	}
+}
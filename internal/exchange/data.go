@@ -0,0 +1,31 @@
+package exchange
+
+// OpenPrices returns the open price of each kline.
+func OpenPrices(klines []Kline) []float64 {
+	values := make([]float64, 0, len(klines))
+	for _, kline := range klines {
+		values = append(values, kline.Open)
+	}
+	return values
+}
+
+// FindTicker returns the ticker for the symbol, false when not found.
+func FindTicker(tickers []Ticker, symbolName string) (Ticker, bool) {
+	for _, t := range tickers {
+		if t.Symbol == symbolName {
+			return t, true
+		}
+	}
+	return Ticker{}, false
+}
+
+// MaximumHigh returns the highest high of the klines.
+func MaximumHigh(klines []Kline) float64 {
+	high := 0.0
+	for _, kline := range klines {
+		if kline.High > high {
+			high = kline.High
+		}
+	}
+	return high
+}
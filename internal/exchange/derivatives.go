@@ -0,0 +1,34 @@
+package exchange
+
+import "time"
+
+// FundingRate the last funding rate of a perpetual.
+type FundingRate struct {
+	Symbol          string    `json:"symbol"`
+	Rate            float64   `json:"rate"` // Rate as a fraction (0.0001 is 0.01%).
+	NextFundingTime time.Time `json:"nextFundingTime"`
+}
+
+// OpenInterest the total open interest of a symbol at a point in time.
+type OpenInterest struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// DerivativesService is implemented by exchanges that provide funding rates and open interest.
+type DerivativesService interface {
+	// GetFundingRates returns the last funding rate for all symbols.
+	GetFundingRates() ([]FundingRate, error)
+	// GetOpenInterestHistory returns the open interest of the last hours, oldest first.
+	GetOpenInterestHistory(symbol Symbol, hours int) ([]OpenInterest, error)
+}
+
+// FindFundingRate returns the funding rate for the symbol, false when not found.
+func FindFundingRate(rates []FundingRate, symbolName string) (FundingRate, bool) {
+	for _, r := range rates {
+		if r.Symbol == symbolName {
+			return r, true
+		}
+	}
+	return FundingRate{}, false
+}
@@ -1,20 +1,75 @@
 package exchange
 
+import "time"
+
+// Symbol a tradeable contract on the exchange.
 type Symbol struct {
-	Name       string
-	BaseAsset  string
-	QuoteAsset string
+	Name        string    `json:"name"`
+	BaseAsset   string    `json:"baseAsset"`
+	QuoteAsset  string    `json:"quoteAsset"`
+	MarginAsset string    `json:"marginAsset"`
+	OnboardDate time.Time `json:"onboardDate"`
+}
+
+// Age returns the time since the symbol was listed on the exchange.
+func (s Symbol) Age() time.Duration {
+	return time.Since(s.OnboardDate)
 }
 
+type BySymbolName []Symbol
+
+func (a BySymbolName) Len() int           { return len(a) }
+func (a BySymbolName) Less(i, j int) bool { return a[i].Name < a[j].Name }
+func (a BySymbolName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+// Ticker the rolling 24 hour statistics of a symbol.
 type Ticker struct {
-	PriceChangePercent24h float64
+	Symbol                string  `json:"symbol"`
+	LastPrice             float64 `json:"lastPrice"`
+	PriceChangePercent24h float64 `json:"priceChangePercent24h"`
+	Volume                float64 `json:"volume"`
+	QuoteVolume           float64 `json:"quoteVolume"`
+	Count                 int64   `json:"count"`
 }
 
+// Kline a single OHLCV candle.
 type Kline struct {
+	OpenTime    time.Time `json:"openTime"`
+	Open        float64   `json:"open"`
+	High        float64   `json:"high"`
+	Low         float64   `json:"low"`
+	Close       float64   `json:"close"`
+	Volume      float64   `json:"volume"`
+	QuoteVolume float64   `json:"quoteVolume"`
+}
+
+type KlineInterval string
+
+const (
+	OneMinute KlineInterval = "1m"
+	OneHour   KlineInterval = "1h"
+	OneDay    KlineInterval = "1d"
+	OneMonth  KlineInterval = "1M"
+)
+
+// Weight the API request weight used by AutoCoins.
+type Weight struct {
+	Used  int
+	Limit int
 }
 
+// ExchangeService is implemented by every exchange AutoCoins can retrieve market data from.
 type ExchangeService interface {
+	// GetSymbols returns all the symbols that are trading.
 	GetSymbols() ([]Symbol, error)
-	GetTickers([]string) ([]Ticker, error)
-	GetKline(string, string, int, int) ([]Kline, error)
+	// GetTickers returns the 24 hour ticker for all symbols.
+	GetTickers() ([]Ticker, error)
+	// GetKline returns the latest `limit` candles for the symbol, oldest first.
+	GetKline(symbol Symbol, interval KlineInterval, limit int) ([]Kline, error)
+	// RateLimitChecks pauses execution when the requests for symbolCount symbols would exceed the rate limit.
+	RateLimitChecks(symbolCount int)
+	// Weight returns the current API weight usage.
+	Weight() Weight
+	// Cancel aborts all running requests.
+	Cancel()
 }
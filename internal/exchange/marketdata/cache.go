@@ -0,0 +1,300 @@
+package marketdata
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+const (
+	DefaultWindow  = 240             // DefaultWindow number of 1 minute klines kept per symbol until a larger limit is requested.
+	MaxKlineAge    = 2 * time.Minute // MaxKlineAge when the latest kline is older the symbol is retrieved using the REST API.
+	MaxTickerAge   = time.Minute     // MaxTickerAge when no ticker update is received within this time the REST API is used.
+	ReconnectDelay = 5 * time.Second // ReconnectDelay time to wait before reconnecting a lost stream.
+)
+
+// Cache implements exchange.ExchangeService. It keeps a rolling window of 1 minute klines and the
+// tickers up to date using the stream of the exchange. The REST API is only used to fill the cache
+// on startup and after the stream reconnects, other kline intervals are passed on to the REST API.
+type Cache struct {
+	Service       exchange.ExchangeService
+	Stream        exchange.StreamService
+	mutex         sync.Mutex
+	series        map[string]*series
+	tickers       map[string]exchange.Ticker
+	tickersSynced bool
+	tickerUpdate  time.Time
+	symbols       []string
+	streamCancel  context.CancelFunc
+	context       context.Context
+	cancel        context.CancelFunc
+}
+
+// series the cached 1 minute klines of a symbol.
+type series struct {
+	klines []exchange.Kline
+	window int
+	synced bool // synced is false until the klines are retrieved using the REST API or when a kline is missed.
+}
+
+func NewCache(service exchange.ExchangeService, stream exchange.StreamService) *Cache {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Cache{
+		Service: service,
+		Stream:  stream,
+		series:  map[string]*series{},
+		tickers: map[string]exchange.Ticker{},
+		context: ctx,
+		cancel:  cancel,
+	}
+}
+
+// GetSymbols returns the symbols from the REST API and (re)starts the stream when the symbols changed.
+func (c *Cache) GetSymbols() ([]exchange.Symbol, error) {
+	symbols, err := c.Service.GetSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	c.startStream(names)
+
+	return symbols, nil
+}
+
+func (c *Cache) startStream(symbols []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.streamCancel != nil && equalStrings(c.symbols, symbols) {
+		return
+	}
+	if c.streamCancel != nil {
+		c.streamCancel()
+	}
+	ctx, cancel := context.WithCancel(c.context)
+	c.streamCancel = cancel
+	c.symbols = symbols
+	c.prune(symbols)
+
+	go c.runStream(ctx, symbols)
+}
+
+func (c *Cache) runStream(ctx context.Context, symbols []string) {
+	logger.Infof("Streaming market data for %d symbols\n", len(symbols))
+	for {
+		err := c.Stream.Stream(ctx, symbols, c)
+		c.invalidate()
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warnf("Market data stream lost: %s\n", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ReconnectDelay):
+		}
+	}
+}
+
+// prune removes the klines and tickers of the symbols that are no longer listed.
+func (c *Cache) prune(symbols []string) {
+	listed := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		listed[s] = true
+	}
+	for name := range c.series {
+		if !listed[name] {
+			delete(c.series, name)
+		}
+	}
+	for name := range c.tickers {
+		if !listed[name] {
+			delete(c.tickers, name)
+		}
+	}
+}
+
+// invalidate marks all the cached data as out of sync, it will be retrieved again using the REST API.
+func (c *Cache) invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, s := range c.series {
+		s.synced = false
+	}
+	c.tickersSynced = false
+}
+
+// HandleKline implements exchange.StreamHandler.
+func (c *Cache) HandleKline(symbol string, kline exchange.Kline) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s, ok := c.series[symbol]
+	if !ok {
+		s = &series{window: DefaultWindow}
+		c.series[symbol] = s
+	}
+	s.add(kline)
+}
+
+// HandleTickers implements exchange.StreamHandler.
+// The trade count is not streamed, the value from the REST API is kept.
+func (c *Cache) HandleTickers(tickers []exchange.Ticker) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, t := range tickers {
+		if current, ok := c.tickers[t.Symbol]; ok {
+			t.Count = current.Count
+		}
+		c.tickers[t.Symbol] = t
+	}
+	c.tickerUpdate = time.Now()
+}
+
+func (c *Cache) GetTickers() ([]exchange.Ticker, error) {
+	c.mutex.Lock()
+	if c.tickersSynced && time.Since(c.tickerUpdate) < MaxTickerAge {
+		tickers := make([]exchange.Ticker, 0, len(c.tickers))
+		for _, t := range c.tickers {
+			tickers = append(tickers, t)
+		}
+		c.mutex.Unlock()
+		return tickers, nil
+	}
+	c.mutex.Unlock()
+
+	tickers, err := c.Service.GetTickers()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, t := range tickers {
+		c.tickers[t.Symbol] = t
+	}
+	c.tickersSynced = true
+	return tickers, nil
+}
+
+// GetKline returns the 1 minute klines from the cache when available.
+func (c *Cache) GetKline(symbol exchange.Symbol, interval exchange.KlineInterval, limit int) ([]exchange.Kline, error) {
+	if interval != exchange.OneMinute {
+		return c.Service.GetKline(symbol, interval, limit)
+	}
+
+	c.mutex.Lock()
+	if s, ok := c.series[symbol.Name]; ok && s.isComplete(limit) {
+		klines := s.last(limit)
+		c.mutex.Unlock()
+		return klines, nil
+	}
+	c.mutex.Unlock()
+
+	klines, err := c.Service.GetKline(symbol, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, ok := c.series[symbol.Name]
+	if !ok {
+		s = &series{window: DefaultWindow}
+		c.series[symbol.Name] = s
+	}
+	if limit > s.window {
+		s.window = limit
+	}
+	s.merge(klines)
+	return s.last(limit), nil
+}
+
+func (c *Cache) RateLimitChecks(symbolCount int) {
+	c.Service.RateLimitChecks(symbolCount)
+}
+
+func (c *Cache) Weight() exchange.Weight {
+	return c.Service.Weight()
+}
+
+func (c *Cache) Cancel() {
+	c.cancel()
+	c.Service.Cancel()
+}
+
+// add updates the current kline or appends a new one.
+func (s *series) add(kline exchange.Kline) {
+	if n := len(s.klines); n > 0 {
+		last := s.klines[n-1]
+		if kline.OpenTime.Equal(last.OpenTime) {
+			s.klines[n-1] = kline
+			return
+		}
+		if kline.OpenTime.Before(last.OpenTime) {
+			return
+		}
+		if kline.OpenTime.Sub(last.OpenTime) > time.Minute {
+			s.synced = false
+		}
+	}
+	s.klines = append(s.klines, kline)
+	s.trim()
+}
+
+// merge replaces the klines with the klines from the REST API.
+// Streamed klines that are newer than the REST API klines are kept.
+func (s *series) merge(klines []exchange.Kline) {
+	streamed := s.klines
+	s.klines = append([]exchange.Kline{}, klines...)
+	s.synced = true
+	for _, k := range streamed {
+		s.add(k)
+	}
+	s.trim()
+}
+
+func (s *series) trim() {
+	if len(s.klines) > s.window {
+		s.klines = append([]exchange.Kline{}, s.klines[len(s.klines)-s.window:]...)
+	}
+}
+
+// isComplete returns true when the last `limit` klines are available and up to date.
+func (s *series) isComplete(limit int) bool {
+	n := len(s.klines)
+	return s.synced && n >= limit && time.Since(s.klines[n-1].OpenTime) < MaxKlineAge
+}
+
+// last returns a copy of the last `limit` klines.
+func (s *series) last(limit int) []exchange.Kline {
+	start := len(s.klines) - limit
+	if start < 0 {
+		start = 0
+	}
+	return append([]exchange.Kline{}, s.klines[start:]...)
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+// restService counts the requests and records the requested kline limits.
+type restService struct {
+	mutex        sync.Mutex
+	klineCalls   int
+	klineLimits  []int
+	tickersCalls int
+	symbols      []string // symbols (optional) the listed symbols, TESTUSDT when not set.
+}
+
+func (s *restService) GetSymbols() ([]exchange.Symbol, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.symbols == nil {
+		return []exchange.Symbol{{Name: "TESTUSDT"}}, nil
+	}
+	symbols := []exchange.Symbol{}
+	for _, name := range s.symbols {
+		symbols = append(symbols, exchange.Symbol{Name: name})
+	}
+	return symbols, nil
+}
+
+func (s *restService) GetTickers() ([]exchange.Ticker, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tickersCalls++
+	return []exchange.Ticker{{Symbol: "TESTUSDT", PriceChangePercent24h: 1, Count: 10}}, nil
+}
+
+func (s *restService) GetKline(symbol exchange.Symbol, interval exchange.KlineInterval, limit int) ([]exchange.Kline, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.klineCalls++
+	s.klineLimits = append(s.klineLimits, limit)
+	return minuteKlines(time.Now().Truncate(time.Minute), limit, 1), nil
+}
+
+func (s *restService) RateLimitChecks(symbolCount int) {}
+func (s *restService) Weight() exchange.Weight         { return exchange.Weight{} }
+func (s *restService) Cancel()                         {}
+
+// channelStream forwards the klines send on the channel to the handler.
+type channelStream struct {
+	klines  chan exchange.Kline
+	handled chan struct{}
+}
+
+func (s *channelStream) Stream(ctx context.Context, symbols []string, handler exchange.StreamHandler) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case k := <-s.klines:
+			handler.HandleKline("TESTUSDT", k)
+			handler.HandleTickers([]exchange.Ticker{{Symbol: "TESTUSDT", PriceChangePercent24h: 2}})
+			s.handled <- struct{}{}
+		}
+	}
+}
+
+// minuteKlines returns count klines with the last one opening at `last`.
+func minuteKlines(last time.Time, count int, price float64) []exchange.Kline {
+	klines := make([]exchange.Kline, count)
+	for i := range klines {
+		klines[i] = exchange.Kline{
+			OpenTime: last.Add(-time.Duration(count-1-i) * time.Minute),
+			Open:     price,
+		}
+	}
+	return klines
+}
+
+func TestCacheUsesStream(t *testing.T) {
+	rest := &restService{}
+	stream := &channelStream{klines: make(chan exchange.Kline), handled: make(chan struct{})}
+	c := NewCache(rest, stream)
+	defer c.Cancel()
+
+	symbols, err := c.GetSymbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Startup: backfill using the REST API.
+	klines, err := c.GetKline(symbols[0], exchange.OneMinute, 240)
+	if err != nil || len(klines) != 240 {
+		t.Fatalf("invalid backfill: %d %v", len(klines), err)
+	}
+	if _, err := c.GetTickers(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Next minute is streamed.
+	next := klines[len(klines)-1].OpenTime.Add(time.Minute)
+	stream.klines <- exchange.Kline{OpenTime: next, Open: 2}
+	<-stream.handled
+
+	klines, err = c.GetKline(symbols[0], exchange.OneMinute, 240)
+	if err != nil || len(klines) != 240 {
+		t.Fatalf("invalid cached klines: %d %v", len(klines), err)
+	}
+	if !klines[239].OpenTime.Equal(next) || klines[239].Open != 2 {
+		t.Errorf("streamed kline not used: %+v", klines[239])
+	}
+	tickers, err := c.GetTickers()
+	if err != nil || len(tickers) != 1 || tickers[0].PriceChangePercent24h != 2 || tickers[0].Count != 10 {
+		t.Errorf("streamed ticker not used: %+v %v", tickers, err)
+	}
+	if rest.klineCalls != 1 || rest.tickersCalls != 1 {
+		t.Errorf("expected one REST call each got klines %d tickers %d", rest.klineCalls, rest.tickersCalls)
+	}
+}
+
+func TestCacheMissedKline(t *testing.T) {
+	rest := &restService{}
+	stream := &channelStream{klines: make(chan exchange.Kline), handled: make(chan struct{})}
+	c := NewCache(rest, stream)
+	defer c.Cancel()
+
+	symbols, _ := c.GetSymbols()
+	klines, _ := c.GetKline(symbols[0], exchange.OneMinute, 240)
+
+	// A gap in the streamed klines requires a new backfill.
+	stream.klines <- exchange.Kline{OpenTime: klines[239].OpenTime.Add(2 * time.Minute)}
+	<-stream.handled
+
+	c.GetKline(symbols[0], exchange.OneMinute, 240)
+	if rest.klineCalls != 2 {
+		t.Errorf("expected backfill after missed kline got %d REST calls", rest.klineCalls)
+	}
+}
+
+func TestCachePrunesDelisted(t *testing.T) {
+	rest := &restService{symbols: []string{"OLDUSDT", "TESTUSDT"}}
+	stream := &channelStream{klines: make(chan exchange.Kline), handled: make(chan struct{})}
+	c := NewCache(rest, stream)
+	defer c.Cancel()
+
+	if _, err := c.GetSymbols(); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Truncate(time.Minute)
+	for _, name := range []string{"OLDUSDT", "TESTUSDT"} {
+		c.HandleKline(name, exchange.Kline{OpenTime: now})
+		c.HandleTickers([]exchange.Ticker{{Symbol: name}})
+	}
+
+	rest.mutex.Lock()
+	rest.symbols = []string{"TESTUSDT"}
+	rest.mutex.Unlock()
+	if _, err := c.GetSymbols(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.series["OLDUSDT"]; ok {
+		t.Errorf("expected the klines of OLDUSDT to be removed")
+	}
+	if _, ok := c.tickers["OLDUSDT"]; ok {
+		t.Errorf("expected the ticker of OLDUSDT to be removed")
+	}
+	if _, ok := c.series["TESTUSDT"]; !ok {
+		t.Errorf("expected the klines of TESTUSDT to be kept")
+	}
+}
@@ -0,0 +1,126 @@
+package marketdata
+
+import (
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/database/klinedb"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// StoreRetention the number of times the requested limit of klines that are kept in the store.
+// Monthly klines are always kept, they are needed for the all time high.
+const StoreRetention = 2
+
+var intervalDurations = map[exchange.KlineInterval]time.Duration{
+	exchange.OneMinute: time.Minute,
+	exchange.OneHour:   time.Hour,
+	exchange.OneDay:    24 * time.Hour,
+}
+
+// Store implements exchange.ExchangeService. It persists the klines so only the klines
+// that are missing since the previous run are retrieved from the exchange.
+type Store struct {
+	Service exchange.ExchangeService
+	DB      *klinedb.Database
+}
+
+func NewStore(service exchange.ExchangeService, db *klinedb.Database) *Store {
+	return &Store{
+		Service: service,
+		DB:      db,
+	}
+}
+
+func (s *Store) GetSymbols() ([]exchange.Symbol, error) {
+	return s.Service.GetSymbols()
+}
+
+func (s *Store) GetTickers() ([]exchange.Ticker, error) {
+	return s.Service.GetTickers()
+}
+
+// GetKline returns the stored klines and only retrieves the klines that are missing.
+// The last stored kline is always retrieved again because it was possibly not closed yet.
+func (s *Store) GetKline(symbol exchange.Symbol, interval exchange.KlineInterval, limit int) ([]exchange.Kline, error) {
+	stored, err := s.DB.SelectKlines(symbol.Name, interval, limit)
+	if err != nil {
+		logger.Errorf("kline store select %s: %s\n", symbol.Name, err.Error())
+		return s.Service.GetKline(symbol, interval, limit)
+	}
+
+	now := time.Now()
+	missing := missingKlines(stored, symbol, interval, limit, now)
+	klines, err := s.Service.GetKline(symbol, interval, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.UpsertKlines(symbol.Name, interval, klines); err != nil {
+		logger.Errorf("kline store insert %s: %s\n", symbol.Name, err.Error())
+	}
+	if d, ok := intervalDurations[interval]; ok {
+		before := now.Add(-time.Duration(limit*StoreRetention) * d)
+		if err := s.DB.DeleteKlinesBefore(symbol.Name, interval, before); err != nil {
+			logger.Errorf("kline store delete %s: %s\n", symbol.Name, err.Error())
+		}
+	}
+
+	return mergeKlines(stored, klines, limit), nil
+}
+
+func (s *Store) RateLimitChecks(symbolCount int) {
+	s.Service.RateLimitChecks(symbolCount)
+}
+
+func (s *Store) Weight() exchange.Weight {
+	return s.Service.Weight()
+}
+
+func (s *Store) Cancel() {
+	s.Service.Cancel()
+}
+
+// missingKlines returns the number of klines to retrieve from the exchange.
+// All klines are retrieved when the stored klines do not go back far enough.
+func missingKlines(stored []exchange.Kline, symbol exchange.Symbol, interval exchange.KlineInterval, limit int, now time.Time) int {
+	if len(stored) == 0 {
+		return limit
+	}
+	if len(stored) < limit && stored[0].OpenTime.After(symbol.OnboardDate) {
+		return limit
+	}
+
+	last := stored[len(stored)-1].OpenTime
+	missing := limit
+	if interval == exchange.OneMonth {
+		missing = (now.Year()-last.Year())*12 + int(now.Month()-last.Month()) + 1
+	} else if d, ok := intervalDurations[interval]; ok {
+		missing = int(now.Sub(last)/d) + 1
+	}
+
+	if missing > limit {
+		return limit
+	}
+	if missing < 1 {
+		return 1
+	}
+	return missing
+}
+
+// mergeKlines replaces the stored klines by the retrieved klines and returns the last `limit` klines.
+func mergeKlines(stored []exchange.Kline, klines []exchange.Kline, limit int) []exchange.Kline {
+	merged := []exchange.Kline{}
+	for _, k := range stored {
+		if len(klines) > 0 && !k.OpenTime.Before(klines[0].OpenTime) {
+			break
+		}
+		merged = append(merged, k)
+	}
+	merged = append(merged, klines...)
+
+	if len(merged) > limit {
+		merged = merged[len(merged)-limit:]
+	}
+	return merged
+}
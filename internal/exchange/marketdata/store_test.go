@@ -0,0 +1,72 @@
+package marketdata
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/database/klinedb"
+	"github.com/LompeBoer/go-autocoins/internal/exchange"
+)
+
+func newTestStore(t *testing.T, service exchange.ExchangeService) *Store {
+	db := klinedb.New(filepath.Join(t.TempDir(), "klines.db"))
+	t.Cleanup(func() { db.Close() })
+	if err := db.CreateKlineTable(); err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(service, db)
+}
+
+func TestStoreOnlyRetrievesMissing(t *testing.T) {
+	rest := &restService{}
+	s := newTestStore(t, rest)
+	symbol := exchange.Symbol{Name: "TESTUSDT", OnboardDate: time.Now().AddDate(-1, 0, 0)}
+
+	for i := 0; i < 2; i++ {
+		klines, err := s.GetKline(symbol, exchange.OneMinute, 240)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(klines) != 240 {
+			t.Fatalf("invalid kline count: expected %d got %d", 240, len(klines))
+		}
+	}
+
+	if len(rest.klineLimits) != 2 || rest.klineLimits[0] != 240 || rest.klineLimits[1] > 2 {
+		t.Errorf("expected full retrieval followed by the last klines got %v", rest.klineLimits)
+	}
+}
+
+func TestMissingKlines(t *testing.T) {
+	now := time.Date(2021, 9, 15, 12, 30, 0, 0, time.UTC)
+	symbol := exchange.Symbol{Name: "TESTUSDT", OnboardDate: time.Date(2020, 2, 10, 0, 0, 0, 0, time.UTC)}
+	months := func(start time.Time, count int) []exchange.Kline {
+		klines := []exchange.Kline{}
+		for i := 0; i < count; i++ {
+			klines = append(klines, exchange.Kline{OpenTime: start.AddDate(0, i, 0)})
+		}
+		return klines
+	}
+
+	tests := []struct {
+		name     string
+		stored   []exchange.Kline
+		interval exchange.KlineInterval
+		limit    int
+		expect   int
+	}{
+		{"empty", nil, exchange.OneMinute, 240, 240},
+		{"minutes", minuteKlines(now.Add(-10*time.Minute), 240, 1), exchange.OneMinute, 240, 11},
+		{"minutes too old", minuteKlines(now.Add(-24*time.Hour), 240, 1), exchange.OneMinute, 240, 240},
+		{"minutes too few", minuteKlines(now, 100, 1), exchange.OneMinute, 240, 240},
+		{"months all history", months(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), 19), exchange.OneMonth, 20, 2},
+		{"months current", months(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), 20), exchange.OneMonth, 20, 1},
+	}
+	for _, test := range tests {
+		got := missingKlines(test.stored, symbol, test.interval, test.limit, now)
+		if got != test.expect {
+			t.Errorf("%s: expected %d got %d", test.name, test.expect, got)
+		}
+	}
+}
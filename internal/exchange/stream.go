@@ -0,0 +1,18 @@
+package exchange
+
+import "context"
+
+// StreamHandler receives the market data pushed by a StreamService.
+type StreamHandler interface {
+	// HandleKline receives updates of the current 1 minute kline of a symbol.
+	HandleKline(symbol string, kline Kline)
+	// HandleTickers receives the tickers that changed.
+	HandleTickers(tickers []Ticker)
+}
+
+// StreamService is implemented by exchanges that can push market data.
+type StreamService interface {
+	// Stream sends the 1 minute klines of the symbols and all tickers to the handler.
+	// It blocks until the context is cancelled or the connection is lost.
+	Stream(ctx context.Context, symbols []string, handler StreamHandler) error
+}
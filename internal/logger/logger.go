@@ -0,0 +1,104 @@
+// Package logger writes leveled log messages using the standard log package.
+// Messages below the level are discarded, warnings and errors are prefixed with their level.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+type Level int32
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+}
+
+var levelPrefixes = map[Level]string{
+	DebugLevel: "DEBUG: ",
+	InfoLevel:  "",
+	WarnLevel:  "WARNING: ",
+	ErrorLevel: "ERROR: ",
+}
+
+var level = int32(InfoLevel)
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel parses debug, info, warn (or warning) and error.
+func ParseLevel(value string) (Level, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "warning" {
+		return WarnLevel, nil
+	}
+	for l, name := range levelNames {
+		if name == value {
+			return l, nil
+		}
+	}
+	return InfoLevel, fmt.Errorf("unknown log level '%s' (use debug, info, warn or error)", value)
+}
+
+// SetLevel sets the minimum level of the messages that are written.
+func SetLevel(l Level) {
+	atomic.StoreInt32(&level, int32(l))
+}
+
+// GetLevel returns the minimum level of the messages that are written.
+func GetLevel() Level {
+	return Level(atomic.LoadInt32(&level))
+}
+
+// SetOutput sets the output of the standard logger.
+func SetOutput(w io.Writer) {
+	log.SetOutput(w)
+}
+
+// Enabled returns true when messages of the level are written.
+func Enabled(l Level) bool {
+	return l >= GetLevel()
+}
+
+func Debugf(format string, v ...interface{}) {
+	output(DebugLevel, format, v...)
+}
+
+func Infof(format string, v ...interface{}) {
+	output(InfoLevel, format, v...)
+}
+
+func Warnf(format string, v ...interface{}) {
+	output(WarnLevel, format, v...)
+}
+
+func Errorf(format string, v ...interface{}) {
+	output(ErrorLevel, format, v...)
+}
+
+// Fatalf writes the error and exits the program.
+func Fatalf(format string, v ...interface{}) {
+	log.Output(2, levelPrefixes[ErrorLevel]+fmt.Sprintf(format, v...))
+	os.Exit(1)
+}
+
+func output(l Level, format string, v ...interface{}) {
+	if !Enabled(l) {
+		return
+	}
+	log.Output(3, levelPrefixes[l]+fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	flags := log.Flags()
+	log.SetFlags(0)
+	SetOutput(&buf)
+	defer func() {
+		SetOutput(os.Stderr)
+		log.SetFlags(flags)
+		SetLevel(InfoLevel)
+	}()
+
+	SetLevel(WarnLevel)
+	Debugf("debug %d", 1)
+	Infof("info %d", 2)
+	Warnf("warn %d", 3)
+	Errorf("error %d\n", 4)
+
+	expected := "WARNING: warn 3\nERROR: error 4\n"
+	if buf.String() != expected {
+		t.Errorf("invalid output: expected %q got %q", expected, buf.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{"debug": DebugLevel, "INFO": InfoLevel, "warning": WarnLevel, "warn": WarnLevel, " error ": ErrorLevel}
+	for value, expected := range tests {
+		l, err := ParseLevel(value)
+		if err != nil || l != expected {
+			t.Errorf("%q: expected %s got %s (%v)", value, expected, l, err)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil || !strings.Contains(err.Error(), "verbose") {
+		t.Errorf("expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,216 @@
+// Package metrics implements counters, gauges and histograms in the Prometheus text exposition format.
+// https://prometheus.io/docs/instrumenting/exposition_formats/
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry holds the metrics that are written by the handler.
+type Registry struct {
+	mutex   sync.Mutex
+	metrics []metric
+}
+
+type metric interface {
+	write(w io.Writer)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// Write writes all the metrics in the text exposition format.
+func (r *Registry) Write(w io.Writer) {
+	r.mutex.Lock()
+	metrics := append([]metric{}, r.metrics...)
+	r.mutex.Unlock()
+
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+// Handler serves the metrics for Prometheus.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Write(w)
+	})
+}
+
+// vector the values of a metric by label values.
+type vector struct {
+	mutex  sync.Mutex
+	name   string
+	help   string
+	kind   string
+	labels []string
+	values map[string]float64
+}
+
+func newVector(name string, help string, kind string, labels []string) *vector {
+	return &vector{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		values: map[string]float64{},
+	}
+}
+
+func (v *vector) key(labelValues []string) string {
+	if len(labelValues) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values got %d", v.name, len(v.labels), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+func (v *vector) write(w io.Writer) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, v.help, v.name, v.kind)
+	keys := make([]string, 0, len(v.values))
+	for k := range v.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		labels := ""
+		if len(v.labels) > 0 {
+			labels = formatLabels(v.labels, strings.Split(k, "\xff"))
+		}
+		fmt.Fprintf(w, "%s%s %s\n", v.name, labels, formatValue(v.values[k]))
+	}
+}
+
+// Counter a value that only increases.
+type Counter struct {
+	vector *vector
+}
+
+func (r *Registry) NewCounter(name string, help string, labels ...string) *Counter {
+	c := &Counter{vector: newVector(name, help, "counter", labels)}
+	r.register(c.vector)
+	return c
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *Counter) Add(value float64, labelValues ...string) {
+	v := c.vector
+	key := v.key(labelValues)
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.values[key] += value
+}
+
+// Gauge a value that can go up and down.
+type Gauge struct {
+	vector *vector
+}
+
+func (r *Registry) NewGauge(name string, help string, labels ...string) *Gauge {
+	g := &Gauge{vector: newVector(name, help, "gauge", labels)}
+	r.register(g.vector)
+	return g
+}
+
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	v := g.vector
+	key := v.key(labelValues)
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.values[key] = value
+}
+
+// Reset removes all the label values.
+func (g *Gauge) Reset() {
+	v := g.vector
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.values = map[string]float64{}
+}
+
+// Histogram counts the observed values in buckets.
+type Histogram struct {
+	mutex   sync.Mutex
+	name    string
+	help    string
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func (r *Registry) NewHistogram(name string, help string, buckets []float64) *Histogram {
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+	h := &Histogram{
+		name:    name,
+		help:    help,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+	r.register(h)
+	return h
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for i, b := range h.buckets {
+		if value <= b {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for i, b := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", h.name, formatValue(b), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", h.name, formatValue(h.sum))
+	fmt.Fprintf(w, "%s_count %d\n", h.name, h.count)
+}
+
+func formatLabels(names []string, values []string) string {
+	pairs := make([]string, 0, len(names))
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, strconv.Quote(values[i])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	r := NewRegistry()
+	runs := r.NewCounter("test_runs_total", "Runs.", "result")
+	symbols := r.NewGauge("test_symbols", "Symbols.")
+	duration := r.NewHistogram("test_duration_seconds", "Duration.", []float64{10, 1})
+
+	runs.Inc("success")
+	runs.Inc("success")
+	runs.Inc("failure")
+	symbols.Set(12)
+	duration.Observe(0.5)
+	duration.Observe(5)
+
+	var b bytes.Buffer
+	r.Write(&b)
+	expected := `# HELP test_runs_total Runs.
+# TYPE test_runs_total counter
+test_runs_total{result="failure"} 1
+test_runs_total{result="success"} 2
+# HELP test_symbols Symbols.
+# TYPE test_symbols gauge
+test_symbols 12
+# HELP test_duration_seconds Duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="1"} 1
+test_duration_seconds_bucket{le="10"} 2
+test_duration_seconds_bucket{le="+Inf"} 2
+test_duration_seconds_sum 5.5
+test_duration_seconds_count 2
+`
+	if b.String() != expected {
+		t.Errorf("invalid output: expected\n%s\ngot\n%s", expected, b.String())
+	}
+}
@@ -6,13 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
+
+	"github.com/LompeBoer/go-autocoins/internal/logger"
 )
 
 const (
@@ -38,14 +39,14 @@ func ReadWithKey(key string) ([]Pair, error) {
 
 	srv, err := sheets.NewService(ctx, option.WithAPIKey(key))
 	if err != nil {
-		log.Printf("Unable to retrieve Sheets client: %v\n", err)
+		logger.Errorf("Unable to retrieve Sheets client: %v\n", err)
 		return nil, err
 	}
 
 	readRange := SheetName + "!" + ReadRange
 	resp, err := srv.Spreadsheets.Values.Get(DocumentID, readRange).Do()
 	if err != nil {
-		log.Printf("Unable to retrieve data from sheet: %v\n", err)
+		logger.Errorf("Unable to retrieve data from sheet: %v\n", err)
 		return nil, err
 	}
 
@@ -74,6 +75,7 @@ func Read() ([]Pair, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
 	list := []Pair{}
 	r := csv.NewReader(resp.Body)
@@ -83,7 +85,7 @@ func Read() ([]Pair, error) {
 			break
 		}
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 
 		list = append(list, convertRow(row))
@@ -0,0 +1,154 @@
+// Package rotate provides a file writer that rotates the file when it reaches a maximum size.
+package rotate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DateLayout the suffix of the files rotated daily.
+const DateLayout = "2006-01-02"
+
+// File appends to a file, when the file would exceed MaxSize it is renamed to `Filename.1`
+// (the older backups are shifted to `.2`, `.3`, ...) and a new file is started.
+// When Daily is set the file is instead renamed to `Filename.2006-01-02` on the first write of a new day.
+type File struct {
+	Filename   string
+	MaxSize    int64 // MaxSize in bytes, 0 never rotates.
+	MaxBackups int   // MaxBackups number of rotated files to keep, older files are removed.
+	Daily      bool  // Daily rotates the file every day instead of by size.
+	mutex      sync.Mutex
+	file       *os.File
+	size       int64
+	day        string
+	clock      func() time.Time
+}
+
+func New(filename string, maxSize int64, maxBackups int) *File {
+	return &File{
+		Filename:   filename,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+	}
+}
+
+// Write appends the data to the file, a single write is never split over two files.
+func (f *File) Write(data []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.Daily {
+		if day := f.now().Format(DateLayout); day != f.day {
+			if f.size > 0 {
+				if err := f.rotateDaily(); err != nil {
+					return 0, err
+				}
+			}
+			f.day = day
+		}
+	} else if f.MaxSize > 0 && f.size > 0 && f.size+int64(len(data)) > f.MaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(data)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file.
+func (f *File) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *File) open() error {
+	file, err := os.OpenFile(f.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	if f.day == "" {
+		f.day = info.ModTime().Format(DateLayout)
+	}
+	return nil
+}
+
+func (f *File) now() time.Time {
+	if f.clock != nil {
+		return f.clock()
+	}
+	return time.Now()
+}
+
+func (f *File) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	if f.MaxBackups > 0 {
+		os.Remove(f.backupName(f.MaxBackups))
+		for i := f.MaxBackups - 1; i > 0; i-- {
+			if err := os.Rename(f.backupName(i), f.backupName(i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(f.Filename, f.backupName(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(f.Filename); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+// rotateDaily renames the file to the day of its messages and removes the oldest files.
+func (f *File) rotateDaily() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	if err := os.Rename(f.Filename, f.Filename+"."+f.day); err != nil {
+		return err
+	}
+	if f.MaxBackups > 0 {
+		files, err := filepath.Glob(f.Filename + ".????-??-??")
+		if err != nil {
+			return err
+		}
+		sort.Strings(files)
+		for i := 0; i < len(files)-f.MaxBackups; i++ {
+			os.Remove(files[i])
+		}
+	}
+	return f.open()
+}
+
+func (f *File) backupName(index int) string {
+	return fmt.Sprintf("%s.%d", f.Filename, index)
+}
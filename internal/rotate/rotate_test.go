@@ -0,0 +1,73 @@
+package rotate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	f := New(filename, 10, 2)
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n", "ffff\n", "gggg\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write returned error: %s", err.Error())
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close returned error: %s", err.Error())
+	}
+
+	expected := map[string]string{
+		filename:        "gggg\n",
+		filename + ".1": "eeee\nffff\n",
+		filename + ".2": "cccc\ndddd\n",
+	}
+	for name, content := range expected {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Errorf("%s: %s", name, err.Error())
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("%s: expected %q got %q", name, content, string(data))
+		}
+	}
+	if _, err := os.Stat(filename + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed", filename+".3")
+	}
+}
+
+func TestFileDaily(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	f := New(filename, 0, 2)
+	f.Daily = true
+	for day := 1; day <= 4; day++ {
+		now := time.Date(2021, 6, day, 12, 0, 0, 0, time.UTC)
+		f.clock = func() time.Time { return now }
+		if _, err := f.Write([]byte(now.Format(DateLayout) + "\n")); err != nil {
+			t.Fatalf("Write returned error: %s", err.Error())
+		}
+	}
+	f.Close()
+
+	expected := map[string]string{
+		filename:                 "2021-06-04\n",
+		filename + ".2021-06-03": "2021-06-03\n",
+		filename + ".2021-06-02": "2021-06-02\n",
+	}
+	for name, content := range expected {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Errorf("%s: %s", name, err.Error())
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("%s: expected %q got %q", name, content, string(data))
+		}
+	}
+	if _, err := os.Stat(filename + ".2021-06-01"); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed", filename+".2021-06-01")
+	}
+}
@@ -0,0 +1,21 @@
+package slack
+
+// Message an incoming webhook message, Text is shown in the notification when there are blocks.
+type Message struct {
+	Text   string  `json:"text"`
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+// Block a Block Kit layout block: header, section, context or divider.
+type Block struct {
+	Type     string       `json:"type"`
+	Text     *TextObject  `json:"text,omitempty"`
+	Fields   []TextObject `json:"fields,omitempty"`
+	Elements []TextObject `json:"elements,omitempty"`
+}
+
+// TextObject a plain_text or mrkdwn text.
+type TextObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
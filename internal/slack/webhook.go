@@ -0,0 +1,142 @@
+// Package slack sends messages to a Slack incoming webhook.
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// The limits of the Slack API.
+const (
+	MaxTextLength       = 3000 // MaxTextLength the maximum number of characters of the text of a section block.
+	MaxBlocksPerMessage = 50
+)
+
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+type WebHook struct {
+	URL string
+}
+
+func (w *WebHook) SendTextMessage(message string) error {
+	return w.SendMessage(Message{
+		Text: message,
+	})
+}
+
+// SendMessage sends the message, a message with too many blocks is sent as multiple messages.
+func (w *WebHook) SendMessage(message Message) error {
+	if w.URL == "" {
+		return nil
+	}
+
+	for len(message.Blocks) > MaxBlocksPerMessage {
+		part := message
+		part.Blocks = message.Blocks[:MaxBlocksPerMessage]
+		if err := w.post(part); err != nil {
+			return err
+		}
+		message.Blocks = message.Blocks[MaxBlocksPerMessage:]
+	}
+	return w.post(message)
+}
+
+func (w *WebHook) post(message Message) error {
+	b, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(w.URL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to post Slack webhook: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (w *WebHook) SendError(message string, mention bool) error {
+	if w.URL == "" {
+		return nil
+	}
+
+	message = Escape(message)
+	if mention {
+		message = "<!here> " + message
+	}
+
+	return w.SendTextMessage(message)
+}
+
+// Header returns a header block.
+func Header(text string) Block {
+	return Block{Type: "header", Text: &TextObject{Type: "plain_text", Text: text}}
+}
+
+// Section returns a section block with mrkdwn text, text that is too long is truncated.
+func Section(text string) Block {
+	return Block{Type: "section", Text: &TextObject{Type: "mrkdwn", Text: Truncate(text, MaxTextLength)}}
+}
+
+// SplitSection returns the mrkdwn value below the bold title as multiple section blocks when it is too long,
+// the value is split at the separator. The title of the following blocks ends with "(continued)".
+func SplitSection(title string, value string, separator string) []Block {
+	blocks := []Block{}
+	current := ""
+	add := func() {
+		t := title
+		if len(blocks) > 0 {
+			t = title + " (continued)"
+		}
+		blocks = append(blocks, Section(fmt.Sprintf("*%s*\n%s", t, current)))
+		current = ""
+	}
+	max := MaxTextLength - utf8.RuneCountInString(title+" (continued)") - 3
+	for _, part := range strings.Split(value, separator) {
+		part = Truncate(part, max)
+		switch {
+		case current == "":
+			current = part
+		case utf8.RuneCountInString(current)+utf8.RuneCountInString(separator)+utf8.RuneCountInString(part) > max:
+			add()
+			current = part
+		default:
+			current += separator + part
+		}
+	}
+	if current != "" || len(blocks) == 0 {
+		add()
+	}
+	return blocks
+}
+
+// Context returns a context block with mrkdwn text.
+func Context(text string) Block {
+	return Block{Type: "context", Elements: []TextObject{{Type: "mrkdwn", Text: text}}}
+}
+
+// Divider returns a divider block.
+func Divider() Block {
+	return Block{Type: "divider"}
+}
+
+// Escape escapes the control characters of mrkdwn.
+func Escape(text string) string {
+	return mrkdwnEscaper.Replace(text)
+}
+
+// Truncate shortens the text to max characters, ending with "…" when truncated.
+func Truncate(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max-1]) + "…"
+}
@@ -0,0 +1,74 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func symbols(n int) string {
+	list := make([]string, n)
+	for i := range list {
+		list[i] = fmt.Sprintf("COIN%03dUSDT (1hr, 4hr)", i)
+	}
+	return strings.Join(list, ", ")
+}
+
+func TestSplitSection(t *testing.T) {
+	blocks := SplitSection("Quarantined", symbols(300), ", ")
+	if len(blocks) < 2 {
+		t.Fatalf("invalid number of blocks: expected more than 1 got %d", len(blocks))
+	}
+	joined := []string{}
+	for i, b := range blocks {
+		if utf8.RuneCountInString(b.Text.Text) > MaxTextLength {
+			t.Errorf("block too long: got %d characters", utf8.RuneCountInString(b.Text.Text))
+		}
+		title := "*Quarantined*\n"
+		if i > 0 {
+			title = "*Quarantined (continued)*\n"
+		}
+		if !strings.HasPrefix(b.Text.Text, title) {
+			t.Errorf("invalid block title: expected %q got %q", title, b.Text.Text[:len(title)])
+		}
+		joined = append(joined, strings.TrimPrefix(b.Text.Text, title))
+	}
+	if strings.Join(joined, ", ") != symbols(300) {
+		t.Errorf("the blocks do not contain all values")
+	}
+
+	blocks = SplitSection("Quarantined", "-", ", ")
+	if len(blocks) != 1 || blocks[0].Text.Text != "*Quarantined*\n-" {
+		t.Errorf("invalid short block: got %+v", blocks)
+	}
+}
+
+func TestSendMessageSplitsBlocks(t *testing.T) {
+	counts := []int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		var msg Message
+		if err := json.Unmarshal(b, &msg); err != nil {
+			t.Errorf("invalid message: %s", err.Error())
+		}
+		counts = append(counts, len(msg.Blocks))
+	}))
+	defer server.Close()
+
+	msg := Message{Text: "report"}
+	for i := 0; i < MaxBlocksPerMessage+10; i++ {
+		msg.Blocks = append(msg.Blocks, Divider())
+	}
+	w := WebHook{URL: server.URL}
+	if err := w.SendMessage(msg); err != nil {
+		t.Fatalf("SendMessage returned error: %s", err.Error())
+	}
+	if len(counts) != 2 || counts[0] != MaxBlocksPerMessage || counts[1] != 10 {
+		t.Errorf("invalid blocks per message: expected [%d 10] got %v", MaxBlocksPerMessage, counts)
+	}
+}
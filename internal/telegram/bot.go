@@ -0,0 +1,146 @@
+// Package telegram sends messages using the Telegram Bot API.
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	DefaultBaseURL   = "https://api.telegram.org"
+	MaxMessageLength = 4096 // MaxMessageLength the maximum number of characters of a message.
+	ParseMode        = "MarkdownV2"
+)
+
+// markdownEscaper escapes the characters that have a meaning in MarkdownV2.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`",
+	">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+type Bot struct {
+	Token   string
+	ChatID  string
+	BaseURL string // BaseURL (optional) the Bot API url, default DefaultBaseURL.
+	client  http.Client
+}
+
+func NewBot(token string, chatID string) *Bot {
+	return &Bot{
+		Token:   token,
+		ChatID:  chatID,
+		BaseURL: DefaultBaseURL,
+		client:  http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendMarkdown sends the MarkdownV2 text, text that is too long is sent as multiple messages.
+// A silent message does not make a sound on the devices of the users.
+func (b *Bot) SendMarkdown(text string, silent bool) error {
+	if b.Token == "" || b.ChatID == "" {
+		return nil
+	}
+	for _, part := range SplitMessage(text, MaxMessageLength) {
+		err := b.SendMessage(SendMessageRequest{
+			ChatID:                b.ChatID,
+			Text:                  part,
+			ParseMode:             ParseMode,
+			DisableNotification:   silent,
+			DisableWebPagePreview: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendError sends the error message as plain text.
+func (b *Bot) SendError(message string, silent bool) error {
+	return b.SendMarkdown(Escape(message), silent)
+}
+
+func (b *Bot) SendMessage(message SendMessageRequest) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	baseURL := b.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	url := fmt.Sprintf("%s/bot%s/sendMessage", baseURL, b.Token)
+	resp, err := b.client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		// The error contains the url with the token.
+		return fmt.Errorf("failed to send Telegram message: %s", strings.ReplaceAll(err.Error(), b.Token, "<token>"))
+	}
+	defer resp.Body.Close()
+
+	var response Response
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to send Telegram message: %s", resp.Status)
+	}
+	if !response.OK {
+		return fmt.Errorf("failed to send Telegram message: %d %s", response.ErrorCode, response.Description)
+	}
+	return nil
+}
+
+// Escape escapes the text to be used in a MarkdownV2 message.
+func Escape(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// SplitMessage splits the text into parts of at most max characters.
+// The text is split at a line break, a line that is too long is split at a space.
+func SplitMessage(text string, max int) []string {
+	parts := []string{}
+	current := ""
+	for _, line := range strings.Split(text, "\n") {
+		for utf8.RuneCountInString(line) > max {
+			head, tail := splitLine(line, max)
+			if current != "" {
+				parts = append(parts, current)
+				current = ""
+			}
+			parts = append(parts, head)
+			line = tail
+		}
+
+		switch {
+		case current == "":
+			current = line
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(line) > max:
+			parts = append(parts, current)
+			current = line
+		default:
+			current += "\n" + line
+		}
+	}
+	if strings.TrimSpace(current) != "" {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// splitLine splits the line at the last space within max characters, an escaped character is not split.
+func splitLine(line string, max int) (string, string) {
+	runes := []rune(line)
+	cut := max
+	for i := max; i > max/2; i-- {
+		if runes[i] == ' ' {
+			return string(runes[:i]), string(runes[i+1:])
+		}
+	}
+	if runes[cut-1] == '\\' {
+		cut--
+	}
+	return string(runes[:cut]), string(runes[cut:])
+}
@@ -0,0 +1,104 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer returns a Bot API stand-in that stores the received messages.
+func newTestServer(t *testing.T, messages *[]SendMessageRequest, response Response) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/botTOKEN/sendMessage" {
+			t.Errorf("invalid path: expected %s got %s", "/botTOKEN/sendMessage", r.URL.Path)
+		}
+		var m SendMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			t.Errorf("invalid body: %s", err.Error())
+		}
+		*messages = append(*messages, m)
+		if !response.OK {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestSendMarkdown(t *testing.T) {
+	messages := []SendMessageRequest{}
+	server := newTestServer(t, &messages, Response{OK: true})
+	defer server.Close()
+
+	bot := NewBot("TOKEN", "-100")
+	bot.BaseURL = server.URL
+	text := strings.Repeat("a", MaxMessageLength-10) + "\n" + strings.Repeat("b", 20)
+	if err := bot.SendMarkdown(text, true); err != nil {
+		t.Fatalf("SendMarkdown returned error: %s", err.Error())
+	}
+	if len(messages) != 2 {
+		t.Fatalf("invalid number of messages: expected %d got %d", 2, len(messages))
+	}
+	m := messages[0]
+	if m.ChatID != "-100" || m.ParseMode != ParseMode || !m.DisableNotification {
+		t.Errorf("invalid message: got %+v", m)
+	}
+	if messages[1].Text != strings.Repeat("b", 20) {
+		t.Errorf("invalid second message: expected %s got %s", strings.Repeat("b", 20), messages[1].Text)
+	}
+}
+
+func TestSendMarkdownError(t *testing.T) {
+	messages := []SendMessageRequest{}
+	server := newTestServer(t, &messages, Response{OK: false, ErrorCode: 400, Description: "Bad Request: can't parse entities"})
+	defer server.Close()
+
+	bot := NewBot("TOKEN", "-100")
+	bot.BaseURL = server.URL
+	err := bot.SendError("failed.", false)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "can't parse entities") {
+		t.Errorf("invalid error: got %s", err.Error())
+	}
+	if len(messages) != 1 || messages[0].Text != `failed\.` || messages[0].DisableNotification {
+		t.Errorf("invalid message: got %+v", messages)
+	}
+}
+
+func TestSendMarkdownDisabled(t *testing.T) {
+	bot := NewBot("", "")
+	if err := bot.SendMarkdown("text", false); err != nil {
+		t.Errorf("expected no error for a bot without token: got %s", err.Error())
+	}
+}
+
+func TestEscape(t *testing.T) {
+	got := Escape("1.5% (BTCUSDT_PERP) -2!")
+	expected := `1\.5% \(BTCUSDT\_PERP\) \-2\!`
+	if got != expected {
+		t.Errorf("Escape: expected %s got %s", expected, got)
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		text     string
+		max      int
+		expected []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"line one\nline two", 10, []string{"line one", "line two"}},
+		{"aaa bbb ccc ddd", 8, []string{"aaa bbb", "ccc ddd"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{`abc\.def`, 4, []string{"abc", `\.de`, "f"}},
+	}
+	for _, test := range tests {
+		got := SplitMessage(test.text, test.max)
+		if strings.Join(got, "|") != strings.Join(test.expected, "|") {
+			t.Errorf("SplitMessage(%q, %d): expected %q got %q", test.text, test.max, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package telegram
+
+// SendMessageRequest the parameters of the sendMessage method.
+// https://core.telegram.org/bots/api#sendmessage
+type SendMessageRequest struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode,omitempty"`
+	DisableNotification   bool   `json:"disable_notification"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// Response the response of every Bot API method.
+type Response struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+}
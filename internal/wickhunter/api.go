@@ -1,9 +1,11 @@
 package wickhunter
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"strconv"
@@ -11,16 +13,31 @@ import (
 	"time"
 )
 
+// API is a client of the local API of the WickHunter bot.
+//
+//	GET /bot/status                    state and version of the bot
+//	GET /bot/positions                 position of every symbol
+//	GET /bot/positions/{symbol}        position of a symbol
+//	GET /symbols                       settings of every symbol
+//	GET /symbols/{symbol}              settings of a symbol
+//	PUT /symbols/{symbol}              update the settings of a symbol
+//	PUT /symbols/{symbol}/enable/{b}   permit (true) or quarantine (false) a symbol
 type API struct {
-	BaseURL string
-	client  http.Client
-	context context.Context
-	cancel  context.CancelFunc
+	BaseURL     string
+	Retries     int           // Retries the number of times a request is retried after a connection error or a 429 or 5xx status.
+	RetryDelay  time.Duration // RetryDelay the delay before the first retry, doubled for every next retry.
+	Concurrency int           // Concurrency the number of symbols UpdatePermittedList updates at the same time.
+	client      http.Client
+	context     context.Context
+	cancel      context.CancelFunc
 }
 
 func NewAPI(apiBaseURL string) *API {
 	api := API{
-		BaseURL: apiBaseURL,
+		BaseURL:     apiBaseURL,
+		Retries:     2,
+		RetryDelay:  500 * time.Millisecond,
+		Concurrency: DefaultConcurrency,
 	}
 	client := http.Client{
 		Timeout: time.Second * 10,
@@ -44,30 +61,34 @@ func NewAPI(apiBaseURL string) *API {
 	return &api
 }
 
-func (a *API) SetSymbolTrading(symbol string, enabled bool) error {
-	url := fmt.Sprintf("%s/symbols/%s/enable/%s", a.BaseURL, strings.ToLower(symbol), strconv.FormatBool(enabled))
-	req, err := http.NewRequest(http.MethodPut, url, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := a.client.Do(req.WithContext(a.context))
-	if err != nil {
-		return err
-	}
-
-	// fmt.Printf("%s (%d)\n", url, resp.StatusCode)
+// WithTimeout returns a copy of the client that does not retry and gives up after the timeout.
+func (a *API) WithTimeout(timeout time.Duration) *API {
+	api := *a
+	api.Retries = 0
+	api.client.Timeout = timeout
+	return &api
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return fmt.Errorf("response status code is '%d' (%s)", resp.StatusCode, resp.Status)
-	}
+// Cancel stops the requests in progress and the retries.
+func (a *API) Cancel() {
+	a.cancel()
+}
 
-	return nil
+func (a *API) SetSymbolTrading(symbol string, enabled bool) error {
+	path := fmt.Sprintf("/symbols/%s/enable/%s", strings.ToLower(symbol), strconv.FormatBool(enabled))
+	return a.request(http.MethodPut, path, nil, nil)
 }
 
 type Position struct {
-	Symbol    string `json:"symbol"`
-	Permitted bool   `json:"permitted"`
-	State     string `json:"state"`
+	Symbol          string  `json:"symbol"`
+	Permitted       bool    `json:"permitted"`
+	State           string  `json:"state"`
+	Side            string  `json:"side,omitempty"`            // Side "Long" or "Short" when the position is open.
+	Size            float64 `json:"size,omitempty"`            // Size the quantity of the position.
+	EntryPrice      float64 `json:"entryPrice,omitempty"`      // EntryPrice the average price of the position.
+	DCACount        int     `json:"dcaCount,omitempty"`        // DCACount the number of DCA buys of the position.
+	TakeProfitPrice float64 `json:"takeProfitPrice,omitempty"` // TakeProfitPrice (optional)
+	StopLossPrice   float64 `json:"stopLossPrice,omitempty"`   // StopLossPrice (optional)
 }
 
 type BySymbolName []Position
@@ -77,24 +98,121 @@ func (a BySymbolName) Less(i, j int) bool { return a[i].Symbol < a[j].Symbol }
 func (a BySymbolName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
 func (a *API) GetPositions() ([]Position, error) {
-	url := fmt.Sprintf("%s/bot/positions", a.BaseURL)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
+	var positions []Position
+	if err := a.request(http.MethodGet, "/bot/positions", nil, &positions); err != nil {
 		return nil, err
 	}
-	resp, err := a.client.Do(req.WithContext(a.context))
-	if err != nil {
+	return positions, nil
+}
+
+// GetPosition returns the position of the symbol, the error is ErrNotFound for an unknown symbol.
+func (a *API) GetPosition(symbol string) (Position, error) {
+	var position Position
+	err := a.request(http.MethodGet, "/bot/positions/"+strings.ToLower(symbol), nil, &position)
+	return position, err
+}
+
+// BotStatus the state and version of the bot.
+type BotStatus struct {
+	State     string    `json:"state"` // State for example "Running" or "Stopped".
+	Version   string    `json:"version"`
+	Exchange  string    `json:"exchange,omitempty"`
+	StartedAt time.Time `json:"startedAt,omitempty"`
+}
+
+func (a *API) GetStatus() (BotStatus, error) {
+	var status BotStatus
+	err := a.request(http.MethodGet, "/bot/status", nil, &status)
+	return status, err
+}
+
+// SymbolSettings the settings of a symbol, the values are only used when DefaultSettings is false.
+type SymbolSettings struct {
+	Symbol          string  `json:"symbol"`
+	Permitted       bool    `json:"permitted"`
+	DefaultSettings bool    `json:"defaultSettings"` // DefaultSettings the symbol uses the global settings of the bot.
+	LongVwap        float64 `json:"longVwap"`
+	ShortVwap       float64 `json:"shortVwap"`
+	LongOffset      float64 `json:"longOffset"`
+	ShortOffset     float64 `json:"shortOffset"`
+	PercentBuy      float64 `json:"percentBuy"`
+	TakeProfit      float64 `json:"takeProfit"`
+	StopLoss        float64 `json:"stopLoss"`
+}
+
+func (a *API) GetSymbols() ([]SymbolSettings, error) {
+	var symbols []SymbolSettings
+	if err := a.request(http.MethodGet, "/symbols", nil, &symbols); err != nil {
 		return nil, err
 	}
+	return symbols, nil
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return nil, fmt.Errorf("response status code is '%d' (%s)", resp.StatusCode, resp.Status)
+// GetSymbol returns the settings of the symbol, the error is ErrNotFound for an unknown symbol.
+func (a *API) GetSymbol(symbol string) (SymbolSettings, error) {
+	var settings SymbolSettings
+	err := a.request(http.MethodGet, "/symbols/"+strings.ToLower(symbol), nil, &settings)
+	return settings, err
+}
+
+// UpdateSymbol replaces the settings of the symbol.
+func (a *API) UpdateSymbol(settings SymbolSettings) error {
+	return a.request(http.MethodPut, "/symbols/"+strings.ToLower(settings.Symbol), settings, nil)
+}
+
+// request sends the request with the body as JSON and decodes the response into out (optional).
+// All requests of the API are idempotent, so they are retried after a connection error or a temporary status.
+func (a *API) request(method string, path string, body interface{}, out interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
 	}
 
-	var positions []Position
-	if err := json.NewDecoder(resp.Body).Decode(&positions); err != nil {
-		return nil, err
+	delay := a.RetryDelay
+	for attempt := 0; ; attempt++ {
+		err := a.do(method, path, data, out)
+		if err == nil || attempt >= a.Retries || !isTemporary(err) {
+			return err
+		}
+		select {
+		case <-a.context.Done():
+			return a.context.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
 	}
+}
 
-	return positions, nil
+func (a *API) do(method string, path string, data []byte, out interface{}) error {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, a.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := a.client.Do(req.WithContext(a.context))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{Method: method, Path: path, StatusCode: resp.StatusCode, Status: resp.Status, Message: strings.TrimSpace(string(message))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response of %s %s: %s", method, path, err.Error())
+	}
+	return nil
 }
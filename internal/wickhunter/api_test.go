@@ -0,0 +1,121 @@
+package wickhunter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter/wickhuntertest"
+)
+
+func newTestAPI(t *testing.T) (*wickhunter.API, *wickhuntertest.Server, func()) {
+	mock := wickhuntertest.New()
+	mock.AddSymbol(wickhunter.SymbolSettings{Symbol: "AAAUSDT", Permitted: true, DefaultSettings: true})
+	mock.AddSymbol(wickhunter.SymbolSettings{Symbol: "BBBUSDT", Permitted: false, DefaultSettings: true})
+	mock.SetPosition(wickhunter.Position{Symbol: "AAAUSDT", State: "Open", Side: "Long", Size: 12, EntryPrice: 1.5, DCACount: 2})
+	server := mock.Start()
+
+	api := wickhunter.NewAPI(server.URL)
+	api.RetryDelay = time.Millisecond
+	return api, mock, server.Close
+}
+
+func TestPositions(t *testing.T) {
+	api, _, stop := newTestAPI(t)
+	defer stop()
+
+	positions, err := api.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions returned error: %s", err.Error())
+	}
+	if len(positions) != 2 {
+		t.Fatalf("invalid number of positions: expected %d got %d", 2, len(positions))
+	}
+	p := positions[0]
+	if p.Symbol != "AAAUSDT" || !p.IsOpen() || p.Side != "Long" || p.Size != 12 || p.EntryPrice != 1.5 || p.DCACount != 2 || !p.Permitted {
+		t.Errorf("invalid position: got %+v", p)
+	}
+	if positions[1].IsOpen() {
+		t.Errorf("expected a neutral position: got %+v", positions[1])
+	}
+
+	p, err = api.GetPosition("bbbusdt")
+	if err != nil || p.Symbol != "BBBUSDT" || p.State != "Neutral" {
+		t.Errorf("invalid position: got %+v (%v)", p, err)
+	}
+	if _, err := api.GetPosition("XYZUSDT"); !errors.Is(err, wickhunter.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown symbol: got %v", err)
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	api, mock, stop := newTestAPI(t)
+	defer stop()
+
+	if err := api.SetSymbolTrading("BBBUSDT", true); err != nil {
+		t.Fatalf("SetSymbolTrading returned error: %s", err.Error())
+	}
+	settings, err := api.GetSymbol("BBBUSDT")
+	if err != nil || !settings.Permitted {
+		t.Errorf("expected a permitted symbol: got %+v (%v)", settings, err)
+	}
+
+	settings.DefaultSettings = false
+	settings.LongVwap = 1.2
+	if err := api.UpdateSymbol(settings); err != nil {
+		t.Fatalf("UpdateSymbol returned error: %s", err.Error())
+	}
+	if s, _ := mock.Symbol("BBBUSDT"); s.DefaultSettings || s.LongVwap != 1.2 {
+		t.Errorf("settings not updated: got %+v", s)
+	}
+
+	symbols, err := api.GetSymbols()
+	if err != nil || len(symbols) != 2 {
+		t.Errorf("invalid symbols: got %+v (%v)", symbols, err)
+	}
+
+	err = api.SetSymbolTrading("XYZUSDT", true)
+	var statusError *wickhunter.StatusError
+	if !errors.As(err, &statusError) || statusError.StatusCode != 404 || !strings.Contains(err.Error(), "/symbols/xyzusdt/enable/true") {
+		t.Errorf("expected a StatusError for an unknown symbol: got %v", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	api, _, stop := newTestAPI(t)
+	defer stop()
+
+	status, err := api.GetStatus()
+	if err != nil || status.State != "Running" || status.Version != "mock" {
+		t.Errorf("invalid status: got %+v (%v)", status, err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	api, mock, stop := newTestAPI(t)
+	defer stop()
+
+	mock.Fail(2)
+	if _, err := api.GetStatus(); err != nil {
+		t.Errorf("expected the request to succeed after 2 retries: got %s", err.Error())
+	}
+	if requests := len(mock.Requests()); requests != 3 {
+		t.Errorf("invalid number of requests: expected %d got %d", 3, requests)
+	}
+
+	mock.Fail(3)
+	_, err := api.GetStatus()
+	var statusError *wickhunter.StatusError
+	if !errors.As(err, &statusError) || !statusError.Temporary() {
+		t.Errorf("expected a temporary StatusError after the retries: got %v", err)
+	}
+
+	// A 404 is not retried.
+	before := len(mock.Requests())
+	api.GetPosition("XYZUSDT")
+	if requests := len(mock.Requests()) - before; requests != 1 {
+		t.Errorf("invalid number of requests for a 404: expected %d got %d", 1, requests)
+	}
+}
@@ -0,0 +1,50 @@
+package wickhunter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ErrNotFound is matched by errors.Is for a 404 response, for example an unknown symbol.
+var ErrNotFound = errors.New("not found")
+
+// StatusError is returned when the bot responds with a status other than 2xx.
+type StatusError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Status     string
+	Message    string // Message the start of the response body.
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s %s: response status code is '%d' (%s)", e.Method, e.Path, e.StatusCode, e.Status)
+	}
+	return fmt.Sprintf("%s %s: response status code is '%d' (%s): %s", e.Method, e.Path, e.StatusCode, e.Status, e.Message)
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// Temporary returns true when the request can be retried.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// isTemporary returns true for a temporary status or a connection error.
+func isTemporary(err error) bool {
+	var statusError *StatusError
+	if errors.As(err, &statusError) {
+		return statusError.Temporary()
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netError net.Error
+	return errors.As(err, &netError)
+}
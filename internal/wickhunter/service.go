@@ -1,23 +1,129 @@
 package wickhunter
 
-import "log"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/LompeBoer/go-autocoins/internal/logger"
+)
+
+// DefaultConcurrency the number of symbols updated at the same time.
+const DefaultConcurrency = 8
+
+// UpdateError is returned when one or more symbols could not be updated.
+type UpdateError struct {
+	Symbols []string          // Symbols that failed to update.
+	Reasons map[string]string // Reasons why each symbol failed.
+	Total   int               // Total number of symbols that were updated.
+}
+
+func (e *UpdateError) Error() string {
+	symbols := make([]string, len(e.Symbols))
+	for i, symbol := range e.Symbols {
+		symbols[i] = fmt.Sprintf("%s (%s)", symbol, e.Reasons[symbol])
+	}
+	return fmt.Sprintf("unable to update %d of %d symbols: %s", len(e.Symbols), e.Total, strings.Join(symbols, ", "))
+}
+
+// UpdatePermittedList permits and quarantines the symbols in WickHunter.
+// Only the symbols of which the permitted state changed are sent, symbols unknown to WickHunter are skipped.
+// The positions are read again afterwards to verify WickHunter applied the changes.
 func (a *API) UpdatePermittedList(permitted []string, quarantined []string) error {
-	for _, symbol := range permitted {
-		err := a.SetSymbolTrading(symbol, true)
-		if err != nil {
-			log.Printf("Error updating permitted symbol: %s\n", err.Error())
+	positions, err := a.GetPositions()
+	if err != nil {
+		return fmt.Errorf("unable to get the WickHunter positions: %s", err.Error())
+	}
+	changes := permittedChanges(positions, permitted, quarantined)
+	if len(changes) == 0 {
+		logger.Debugf("WickHunter permitted list is up to date\n")
+		return nil
+	}
+
+	reasons := a.setSymbolsTrading(changes)
+
+	// Verify the symbols that were updated.
+	positions, err = a.GetPositions()
+	if err != nil {
+		return fmt.Errorf("unable to verify the WickHunter permitted list: %s", err.Error())
+	}
+	for _, p := range positions {
+		if enabled, ok := changes[p.Symbol]; ok && reasons[p.Symbol] == "" && p.Permitted != enabled {
+			reasons[p.Symbol] = "not applied by WickHunter"
 		}
 	}
-	for _, symbol := range quarantined {
-		err := a.SetSymbolTrading(symbol, false)
-		if err != nil {
-			log.Printf("Error updating quarantined symbol: %s\n", err.Error())
+
+	failed := []string{}
+	for symbol, reason := range reasons {
+		if reason != "" {
+			logger.Errorf("unable to update symbol %s: %s\n", symbol, reason)
+			failed = append(failed, symbol)
 		}
 	}
+	logger.Infof("Updated %d symbols in WickHunter\n", len(changes)-len(failed))
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return &UpdateError{Symbols: failed, Reasons: reasons, Total: len(changes)}
+	}
 	return nil
 }
 
+// permittedChanges returns the symbols of which the permitted state differs from the positions and the new state.
+func permittedChanges(positions []Position, permitted []string, quarantined []string) map[string]bool {
+	current := make(map[string]bool, len(positions))
+	for _, p := range positions {
+		current[p.Symbol] = p.Permitted
+	}
+	changes := map[string]bool{}
+	add := func(symbols []string, enabled bool) {
+		for _, symbol := range symbols {
+			state, ok := current[symbol]
+			if !ok {
+				logger.Debugf("Skipping %s, unknown to WickHunter\n", symbol)
+				continue
+			}
+			if state != enabled {
+				changes[symbol] = enabled
+			}
+		}
+	}
+	add(permitted, true)
+	add(quarantined, false)
+	return changes
+}
+
+// setSymbolsTrading updates the symbols with at most Concurrency requests at the same time.
+// Returns the symbols with the error message, an empty message when the update succeeded.
+func (a *API) setSymbolsTrading(changes map[string]bool) map[string]string {
+	concurrency := a.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	reasons := make(map[string]string, len(changes))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for symbol, enabled := range changes {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(symbol string, enabled bool) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			reason := ""
+			if err := a.SetSymbolTrading(symbol, enabled); err != nil {
+				reason = err.Error()
+			}
+			mutex.Lock()
+			reasons[symbol] = reason
+			mutex.Unlock()
+		}(symbol, enabled)
+	}
+	wg.Wait()
+	return reasons
+}
+
 func (p *Position) IsOpen() bool {
 	return (p.State != "Neutral")
 }
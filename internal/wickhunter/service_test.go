@@ -0,0 +1,82 @@
+package wickhunter_test
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
+)
+
+func TestUpdatePermittedList(t *testing.T) {
+	api, mock, stop := newTestAPI(t)
+	defer stop()
+	mock.AddSymbol(wickhunter.SymbolSettings{Symbol: "CCCUSDT", Permitted: true})
+
+	// AAAUSDT is already permitted, CCCUSDT is quarantined, BBBUSDT is permitted and XYZUSDT is unknown.
+	before := len(mock.Requests())
+	err := api.UpdatePermittedList([]string{"AAAUSDT", "BBBUSDT", "XYZUSDT"}, []string{"CCCUSDT"})
+	if err != nil {
+		t.Fatalf("UpdatePermittedList returned error: %s", err.Error())
+	}
+
+	puts := []string{}
+	for _, r := range mock.Requests()[before:] {
+		if strings.HasPrefix(r, "PUT") {
+			puts = append(puts, r)
+		}
+	}
+	sort.Strings(puts)
+	expected := []string{"PUT /symbols/bbbusdt/enable/true", "PUT /symbols/cccusdt/enable/false"}
+	if strings.Join(puts, "|") != strings.Join(expected, "|") {
+		t.Errorf("invalid updates: expected %v got %v", expected, puts)
+	}
+	if s, _ := mock.Symbol("CCCUSDT"); s.Permitted {
+		t.Errorf("CCCUSDT not quarantined")
+	}
+
+	// Nothing changed, only the positions are read.
+	before = len(mock.Requests())
+	if err := api.UpdatePermittedList([]string{"AAAUSDT", "BBBUSDT"}, []string{"CCCUSDT"}); err != nil {
+		t.Fatalf("UpdatePermittedList returned error: %s", err.Error())
+	}
+	if requests := mock.Requests()[before:]; len(requests) != 1 {
+		t.Errorf("expected only the positions to be read: got %v", requests)
+	}
+}
+
+func TestUpdatePermittedListError(t *testing.T) {
+	api, mock, stop := newTestAPI(t)
+	defer stop()
+	mock.AddSymbol(wickhunter.SymbolSettings{Symbol: "CCCUSDT", Permitted: true})
+	mock.AddSymbol(wickhunter.SymbolSettings{Symbol: "DDDUSDT", Permitted: true})
+	mock.FailSymbol("BBBUSDT")
+	mock.IgnoreSymbol("CCCUSDT")
+
+	err := api.UpdatePermittedList([]string{"BBBUSDT"}, []string{"CCCUSDT", "DDDUSDT"})
+	var updateError *wickhunter.UpdateError
+	if !errors.As(err, &updateError) {
+		t.Fatalf("expected an UpdateError: got %v", err)
+	}
+	if updateError.Total != 3 || strings.Join(updateError.Symbols, ",") != "BBBUSDT,CCCUSDT" {
+		t.Errorf("invalid UpdateError: got %+v", updateError)
+	}
+	if !strings.Contains(updateError.Reasons["BBBUSDT"], "500") || updateError.Reasons["CCCUSDT"] != "not applied by WickHunter" {
+		t.Errorf("invalid reasons: got %v", updateError.Reasons)
+	}
+	if s, _ := mock.Symbol("DDDUSDT"); s.Permitted {
+		t.Errorf("DDDUSDT not quarantined")
+	}
+
+	// The failed update is retried.
+	updates := 0
+	for _, r := range mock.Requests() {
+		if r == "PUT /symbols/bbbusdt/enable/true" {
+			updates++
+		}
+	}
+	if updates != api.Retries+1 {
+		t.Errorf("invalid number of updates of BBBUSDT: expected %d got %d", api.Retries+1, updates)
+	}
+}
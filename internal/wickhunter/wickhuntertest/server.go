@@ -0,0 +1,202 @@
+// Package wickhuntertest is an in-memory stand-in of the local API of the WickHunter bot for tests.
+package wickhuntertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/LompeBoer/go-autocoins/internal/wickhunter"
+)
+
+// Server serves the endpoints of wickhunter.API from memory.
+type Server struct {
+	Status    wickhunter.BotStatus
+	mutex     sync.Mutex
+	symbols   map[string]wickhunter.SymbolSettings
+	positions map[string]wickhunter.Position
+	failures  int
+	failing   map[string]bool // failing the symbols of which the updates fail.
+	ignored   map[string]bool // ignored the symbols of which the updates succeed without a change.
+	requests  []string
+}
+
+func New() *Server {
+	return &Server{
+		Status:    wickhunter.BotStatus{State: "Running", Version: "mock"},
+		symbols:   map[string]wickhunter.SymbolSettings{},
+		positions: map[string]wickhunter.Position{},
+		failing:   map[string]bool{},
+		ignored:   map[string]bool{},
+	}
+}
+
+// Start starts an HTTP server with the handler, the url is server.URL.
+func (s *Server) Start() *httptest.Server {
+	return httptest.NewServer(s.Handler())
+}
+
+// AddSymbol adds or replaces the settings of a symbol.
+func (s *Server) AddSymbol(settings wickhunter.SymbolSettings) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	settings.Symbol = strings.ToUpper(settings.Symbol)
+	s.symbols[settings.Symbol] = settings
+}
+
+// SetPosition sets the position of a symbol, the symbol has to be added first.
+func (s *Server) SetPosition(position wickhunter.Position) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	position.Symbol = strings.ToUpper(position.Symbol)
+	s.positions[position.Symbol] = position
+}
+
+// Symbol returns the settings of a symbol.
+func (s *Server) Symbol(symbol string) (wickhunter.SymbolSettings, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	settings, ok := s.symbols[strings.ToUpper(symbol)]
+	return settings, ok
+}
+
+// Fail responds to the next n requests with 503 Service Unavailable.
+func (s *Server) Fail(n int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failures = n
+}
+
+// FailSymbol responds to the updates of the symbol with 500 Internal Server Error.
+func (s *Server) FailSymbol(symbol string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failing[strings.ToUpper(symbol)] = true
+}
+
+// IgnoreSymbol responds to the updates of the symbol with 200 OK without changing it.
+func (s *Server) IgnoreSymbol(symbol string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ignored[strings.ToUpper(symbol)] = true
+}
+
+// Requests returns the method and path of every request received.
+func (s *Server) Requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(s.serveHTTP)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	if s.failures > 0 {
+		s.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/bot/status":
+		writeJSON(w, s.Status)
+	case r.Method == http.MethodGet && r.URL.Path == "/bot/positions":
+		positions := []wickhunter.Position{}
+		for _, symbol := range s.sortedSymbols() {
+			positions = append(positions, s.position(symbol))
+		}
+		writeJSON(w, positions)
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[0] == "bot" && parts[1] == "positions":
+		symbol := strings.ToUpper(parts[2])
+		if _, ok := s.symbols[symbol]; !ok {
+			http.Error(w, "unknown symbol", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, s.position(symbol))
+	case r.Method == http.MethodGet && r.URL.Path == "/symbols":
+		symbols := []wickhunter.SymbolSettings{}
+		for _, symbol := range s.sortedSymbols() {
+			symbols = append(symbols, s.symbols[symbol])
+		}
+		writeJSON(w, symbols)
+	case len(parts) == 2 && parts[0] == "symbols":
+		s.handleSymbol(w, r, strings.ToUpper(parts[1]))
+	case r.Method == http.MethodPut && len(parts) == 4 && parts[0] == "symbols" && parts[2] == "enable":
+		symbol := strings.ToUpper(parts[1])
+		enabled, err := strconv.ParseBool(parts[3])
+		settings, ok := s.symbols[symbol]
+		if err != nil || !ok {
+			http.Error(w, "unknown symbol", http.StatusNotFound)
+			return
+		}
+		if s.failing[symbol] {
+			http.Error(w, "unable to update symbol", http.StatusInternalServerError)
+			return
+		}
+		if !s.ignored[symbol] {
+			settings.Permitted = enabled
+			s.symbols[symbol] = settings
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleSymbol(w http.ResponseWriter, r *http.Request, symbol string) {
+	settings, ok := s.symbols[symbol]
+	if !ok {
+		http.Error(w, "unknown symbol", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, settings)
+	case http.MethodPut:
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		settings.Symbol = symbol
+		s.symbols[symbol] = settings
+		writeJSON(w, settings)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// position returns the position of the symbol, the state is Neutral without a position.
+func (s *Server) position(symbol string) wickhunter.Position {
+	position, ok := s.positions[symbol]
+	if !ok {
+		position = wickhunter.Position{Symbol: symbol, State: "Neutral"}
+	}
+	position.Permitted = s.symbols[symbol].Permitted
+	return position
+}
+
+func (s *Server) sortedSymbols() []string {
+	symbols := make([]string, 0, len(s.symbols))
+	for symbol := range s.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Expires", "0")
+	w.Header().Set("Pragma", "no-cache")
+	json.NewEncoder(w).Encode(value)
+}